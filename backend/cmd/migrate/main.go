@@ -0,0 +1,106 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/self-learning/backend/internal/config"
+	"github.com/self-learning/backend/internal/database"
+)
+
+func main() {
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		usage()
+		os.Exit(1)
+	}
+
+	cfg := config.Load()
+	sqlDB, err := openSQLDB(cfg)
+	if err != nil {
+		log.Fatalf("Failed to open database: %v", err)
+	}
+	defer sqlDB.Close()
+
+	m, err := database.NewMigrator(sqlDB, cfg.DBDriver)
+	if err != nil {
+		log.Fatalf("Failed to initialize migrator: %v", err)
+	}
+	defer m.Close()
+
+	switch args[0] {
+	case "up":
+		runMigration(m.Up)
+	case "down":
+		if len(args) != 2 {
+			log.Fatal("usage: migrate down <N>")
+		}
+		n, err := strconv.Atoi(args[1])
+		if err != nil {
+			log.Fatalf("invalid step count %q: %v", args[1], err)
+		}
+		runMigration(func() error { return m.Steps(-n) })
+	case "force":
+		if len(args) != 2 {
+			log.Fatal("usage: migrate force <V>")
+		}
+		v, err := strconv.Atoi(args[1])
+		if err != nil {
+			log.Fatalf("invalid version %q: %v", args[1], err)
+		}
+		if err := m.Force(v); err != nil {
+			log.Fatalf("Failed to force version %d: %v", v, err)
+		}
+		log.Printf("Forced schema version to %d", v)
+	case "version":
+		v, dirty, err := m.Version()
+		if errors.Is(err, migrate.ErrNilVersion) {
+			fmt.Println("no migrations applied")
+			return
+		}
+		if err != nil {
+			log.Fatalf("Failed to read version: %v", err)
+		}
+		fmt.Printf("version %d (dirty=%t)\n", v, dirty)
+	default:
+		usage()
+		os.Exit(1)
+	}
+}
+
+// openSQLDB opens a raw *sql.DB for the same driver and database as
+// database.Connect, without pulling in GORM. Unlike Connect, its sqlite DSN
+// carries no _txlock option.
+func openSQLDB(cfg *config.Config) (*sql.DB, error) {
+	if cfg.DBDriver == "postgres" {
+		dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+			cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBPassword, cfg.DBName)
+		return sql.Open("postgres", dsn)
+	}
+
+	dbPath := cfg.DatabasePath
+	if dbPath == "" {
+		dbPath = "riseapp.db"
+	}
+	return sql.Open("sqlite3", dbPath)
+}
+
+func runMigration(step func() error) {
+	if err := step(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		log.Fatalf("Migration failed: %v", err)
+	}
+}
+
+func usage() {
+	fmt.Println("usage: migrate <up|down N|force V|version>")
+}
@@ -12,7 +12,7 @@ import (
 func main() {
 	// 1. Load config and connect to DB
 	cfg := config.Load()
-	db := database.Connect(cfg.DatabasePath)
+	db := database.Connect(cfg)
 
 	// 2. Define a dummy account that needs syncing
 	newAccount := models.Account{
@@ -1,34 +1,38 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
-	"github.com/self-learning/backend/internal/config"
-	"github.com/self-learning/backend/internal/database"
-	"github.com/self-learning/backend/internal/ingestion"
-	"github.com/self-learning/backend/internal/server"
+	"github.com/self-learning/backend/internal/app"
 )
 
-func main() {
-	cfg := config.Load()
-
-	srv := server.New(cfg)
-
-	// 1. Initialize Database
-	db := database.Connect("riseapp.db")
+// shutdownTimeout bounds how long graceful shutdown -- draining the HTTP
+// server and ingestion pipeline, then closing the DB -- is allowed to take
+// before the process exits anyway.
+const shutdownTimeout = 30 * time.Second
 
-	// 2. Create the internal Queue (Buffered Channel)
-	// This acts as the "Queue" between Fetcher and Normalizer
-	queue := make(chan ingestion.SyncJob, 100)
+func main() {
+	application := app.New()
 
-	// 3. Start the Background Fetcher (The Engine)
-	go ingestion.StartFetcherLoop(db, queue)
+	startCtx, cancelStart := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancelStart()
+	if err := application.Start(startCtx); err != nil {
+		log.Fatalf("Failed to start: %v", err)
+	}
 
-	// 4. Start the Real Normalizer (The Chef - Loop B)
-	go ingestion.StartNormalizerLoop(db, queue)
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	<-sigCh
+	log.Println("Shutdown signal received, draining...")
 
-	log.Printf("Server starting on port %s", cfg.Port)
-	if err := srv.Start(); err != nil {
-		log.Fatal(err)
+	stopCtx, cancelStop := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelStop()
+	if err := application.Stop(stopCtx); err != nil {
+		log.Fatalf("Failed to shut down cleanly: %v", err)
 	}
 }
@@ -8,7 +8,10 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var jwtKey = []byte("TempTestForLearningKey") // CHANGE THIS!
+// accessTokenTTL is how long an access token issued by GenerateToken stays
+// valid. It's intentionally short; client-side persistence of the session
+// is the refresh token's job (see GenerateRefreshToken).
+const accessTokenTTL = 5 * time.Minute
 
 type CustomClaims struct {
 	UserID   string `json:"user_id"`
@@ -16,30 +19,47 @@ type CustomClaims struct {
 	jwt.RegisteredClaims
 }
 
-// GenerateToken creates a new JWT string for a given user.
-func GenerateToken(userID, username string) (string, error) {
-	// 1. Define the Claims (Payload)
-	//expirationTime := time.Now().Add(5 * time.Minute) // Access token should be short-lived (e.g., 5 min)
-
+// GenerateToken creates a new signed JWT for the given user. secret is the
+// caller's JWT signing key (config.Config.JWTSecret), never hardcoded here.
+func GenerateToken(userID, username string, secret []byte) (string, error) {
+	now := time.Now()
 	claims := &CustomClaims{
 		UserID:   userID,
 		Username: username,
 		RegisteredClaims: jwt.RegisteredClaims{
-			IssuedAt: jwt.NewNumericDate(time.Now()),
-			Subject:  userID, // The principal of the token (the user)
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(accessTokenTTL)),
+			Subject:   userID, // The principal of the token (the user)
 		},
 	}
 
-	// 2. Create the Token object
 	// Use jwt.SigningMethodHS256 for HMAC-SHA256
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
-	// 3. Sign the token to get the complete encoded string
-	tokenString, err := token.SignedString(jwtKey)
-
+	tokenString, err := token.SignedString(secret)
 	if err != nil {
 		return "", fmt.Errorf("could not sign token: %w", err)
 	}
 
 	return tokenString, nil
 }
+
+// ParseToken verifies tokenString's signature against secret and returns its
+// claims, rejecting anything expired, malformed, or signed some other way.
+func ParseToken(tokenString string, secret []byte) (*CustomClaims, error) {
+	claims := &CustomClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return secret, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not parse token: %w", err)
+	}
+	if !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	return claims, nil
+}
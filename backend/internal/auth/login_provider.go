@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/self-learning/backend/internal/config"
+	"golang.org/x/oauth2"
+)
+
+// OAuthUserInfo is the normalized identity a LoginProvider returns from its
+// userinfo endpoint, used to upsert the matching User row.
+type OAuthUserInfo struct {
+	Subject string
+	Email   string
+	Name    string
+}
+
+// LoginProvider is an OAuth2/OIDC identity provider a user can sign in with
+// instead of a username/password. Implementations are looked up by name
+// ("google", "github") via NewLoginProviderRegistry.
+type LoginProvider interface {
+	// AuthURL builds the provider's authorization URL for the given
+	// anti-CSRF state value.
+	AuthURL(state string) string
+
+	// Exchange trades an authorization code (from the OAuth2 callback) for
+	// a token.
+	Exchange(ctx context.Context, code string) (*oauth2.Token, error)
+
+	// FetchUserInfo calls the provider's userinfo endpoint with token.
+	FetchUserInfo(ctx context.Context, token *oauth2.Token) (*OAuthUserInfo, error)
+}
+
+// NewLoginProviderRegistry builds the set of LoginProviders the OAuth login
+// routes can redirect to, keyed by the same name used in
+// /api/v1/auth/oauth/{provider}/....
+func NewLoginProviderRegistry(cfgs map[string]config.OAuthProviderConfig) map[string]LoginProvider {
+	registry := make(map[string]LoginProvider, len(cfgs))
+	for name, cfg := range cfgs {
+		switch name {
+		case "google":
+			registry[name] = NewGoogleLoginProvider(cfg)
+		case "github":
+			registry[name] = NewGitHubLoginProvider(cfg)
+		}
+	}
+	return registry
+}
@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/self-learning/backend/internal/config"
+	"golang.org/x/oauth2"
+)
+
+// GitHub's well-known OAuth2 endpoints.
+const (
+	githubAuthURL     = "https://github.com/login/oauth/authorize"
+	githubTokenURL    = "https://github.com/login/oauth/access_token"
+	githubUserInfoURL = "https://api.github.com/user"
+	githubEmailsURL   = "https://api.github.com/user/emails"
+)
+
+// GitHubLoginProvider signs users in with their GitHub account.
+type GitHubLoginProvider struct {
+	oauthConfig oauth2.Config
+}
+
+func NewGitHubLoginProvider(cfg config.OAuthProviderConfig) *GitHubLoginProvider {
+	return &GitHubLoginProvider{
+		oauthConfig: oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Scopes:       []string{"read:user", "user:email"},
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  githubAuthURL,
+				TokenURL: githubTokenURL,
+			},
+		},
+	}
+}
+
+func (p *GitHubLoginProvider) AuthURL(state string) string {
+	return p.oauthConfig.AuthCodeURL(state)
+}
+
+func (p *GitHubLoginProvider) Exchange(ctx context.Context, code string) (*oauth2.Token, error) {
+	tok, err := p.oauthConfig.Exchange(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("github oauth2 exchange failed: %w", err)
+	}
+	return tok, nil
+}
+
+func (p *GitHubLoginProvider) FetchUserInfo(ctx context.Context, token *oauth2.Token) (*OAuthUserInfo, error) {
+	client := p.oauthConfig.Client(ctx, token)
+	resp, err := client.Get(githubUserInfoURL)
+	if err != nil {
+		return nil, fmt.Errorf("github userinfo request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("github userinfo request returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		ID    int    `json:"id"`
+		Email string `json:"email"`
+		Name  string `json:"name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode github userinfo response: %w", err)
+	}
+
+	email := body.Email
+	if email == "" {
+		// GitHub omits email from /user unless it's public; fall back to
+		// the dedicated emails endpoint for the verified primary one.
+		email, _ = p.fetchPrimaryEmail(client)
+	}
+
+	return &OAuthUserInfo{Subject: strconv.Itoa(body.ID), Email: email, Name: body.Name}, nil
+}
+
+func (p *GitHubLoginProvider) fetchPrimaryEmail(client *http.Client) (string, error) {
+	resp, err := client.Get(githubEmailsURL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&emails); err != nil {
+		return "", err
+	}
+	for _, e := range emails {
+		if e.Primary && e.Verified {
+			return e.Email, nil
+		}
+	}
+	return "", fmt.Errorf("no verified primary email")
+}
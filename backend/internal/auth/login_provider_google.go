@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/self-learning/backend/internal/config"
+	"golang.org/x/oauth2"
+)
+
+// Google's well-known OAuth2/OIDC endpoints.
+const (
+	googleAuthURL     = "https://accounts.google.com/o/oauth2/v2/auth"
+	googleTokenURL    = "https://oauth2.googleapis.com/token"
+	googleUserInfoURL = "https://openidconnect.googleapis.com/v1/userinfo"
+)
+
+// GoogleLoginProvider signs users in with their Google account.
+type GoogleLoginProvider struct {
+	oauthConfig oauth2.Config
+}
+
+func NewGoogleLoginProvider(cfg config.OAuthProviderConfig) *GoogleLoginProvider {
+	return &GoogleLoginProvider{
+		oauthConfig: oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Scopes:       []string{"openid", "email", "profile"},
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  googleAuthURL,
+				TokenURL: googleTokenURL,
+			},
+		},
+	}
+}
+
+func (p *GoogleLoginProvider) AuthURL(state string) string {
+	return p.oauthConfig.AuthCodeURL(state)
+}
+
+func (p *GoogleLoginProvider) Exchange(ctx context.Context, code string) (*oauth2.Token, error) {
+	tok, err := p.oauthConfig.Exchange(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("google oauth2 exchange failed: %w", err)
+	}
+	return tok, nil
+}
+
+func (p *GoogleLoginProvider) FetchUserInfo(ctx context.Context, token *oauth2.Token) (*OAuthUserInfo, error) {
+	client := p.oauthConfig.Client(ctx, token)
+	resp, err := client.Get(googleUserInfoURL)
+	if err != nil {
+		return nil, fmt.Errorf("google userinfo request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("google userinfo request returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Sub   string `json:"sub"`
+		Email string `json:"email"`
+		Name  string `json:"name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode google userinfo response: %w", err)
+	}
+
+	return &OAuthUserInfo{Subject: body.Sub, Email: body.Email, Name: body.Name}, nil
+}
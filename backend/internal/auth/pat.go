@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/self-learning/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// PATPrefix marks a bearer token as a personal access token rather than a
+// JWT issued by POST /api/v1/auth/login, so the auth middleware chain knows
+// which validator to use.
+const PATPrefix = "sl_pat_"
+
+// ErrInvalidToken covers both "no such token" and "token not usable right
+// now" (expired, wrong scope) so callers can't distinguish the two cases.
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// GeneratePAT returns a new random plaintext personal access token and the
+// hash that should be persisted in its place. The plaintext is never stored
+// anywhere and can't be recovered once it's returned.
+func GeneratePAT() (plaintext, hash string, err error) {
+	raw := make([]byte, 24)
+	if _, err = rand.Read(raw); err != nil {
+		return "", "", err
+	}
+	plaintext = PATPrefix + hex.EncodeToString(raw)
+	return plaintext, HashPAT(plaintext), nil
+}
+
+// HashPAT returns the SHA-256 hash of a plaintext token, hex-encoded.
+func HashPAT(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+// ValidatePAT looks up tokenString by its hash and rejects it if it's
+// expired or missing requiredScope (pass "" to skip the scope check). On
+// success it stamps LastUsedAt.
+func ValidatePAT(db *gorm.DB, tokenString, requiredScope string) (*models.PersonalAccessToken, error) {
+	var pat models.PersonalAccessToken
+	if err := db.Where("token_hash = ?", HashPAT(tokenString)).First(&pat).Error; err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if !pat.ExpiresAt.IsZero() && time.Now().After(pat.ExpiresAt) {
+		return nil, ErrInvalidToken
+	}
+
+	if requiredScope != "" && !pat.HasScope(requiredScope) {
+		return nil, ErrInvalidToken
+	}
+
+	now := time.Now()
+	pat.LastUsedAt = &now
+	db.Model(&pat).Update("last_used_at", now)
+
+	return &pat, nil
+}
@@ -0,0 +1,84 @@
+package auth_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/self-learning/backend/internal/auth"
+	"github.com/self-learning/backend/internal/models"
+	"github.com/self-learning/backend/internal/testutil"
+	"gorm.io/gorm"
+)
+
+func createPAT(t *testing.T, db *gorm.DB, scopes []string, expiresAt time.Time) string {
+	t.Helper()
+
+	plaintext, hash, err := auth.GeneratePAT()
+	if err != nil {
+		t.Fatalf("GeneratePAT: %v", err)
+	}
+
+	pat := &models.PersonalAccessToken{
+		ID:        uuid.New().String(),
+		UserID:    uuid.New().String(),
+		Name:      "test token",
+		TokenHash: hash,
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}
+	if err := db.Create(pat).Error; err != nil {
+		t.Fatalf("failed to insert PAT: %v", err)
+	}
+
+	return plaintext
+}
+
+func TestGeneratePATRoundTripsThroughHashPAT(t *testing.T) {
+	plaintext, hash, err := auth.GeneratePAT()
+	if err != nil {
+		t.Fatalf("GeneratePAT: %v", err)
+	}
+	if got := auth.HashPAT(plaintext); got != hash {
+		t.Errorf("HashPAT(plaintext) = %q, want %q", got, hash)
+	}
+}
+
+func TestValidatePATAcceptsAKnownUnexpiredToken(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	plaintext := createPAT(t, db, []string{"api"}, time.Now().Add(time.Hour))
+
+	pat, err := auth.ValidatePAT(db, plaintext, "")
+	if err != nil {
+		t.Fatalf("ValidatePAT returned an error for a valid token: %v", err)
+	}
+	if pat.LastUsedAt == nil {
+		t.Error("ValidatePAT did not stamp LastUsedAt")
+	}
+}
+
+func TestValidatePATRejectsAnUnknownToken(t *testing.T) {
+	db := testutil.NewTestDB(t)
+
+	if _, err := auth.ValidatePAT(db, "sl_pat_doesnotexist", ""); err != auth.ErrInvalidToken {
+		t.Errorf("ValidatePAT(unknown) = %v, want %v", err, auth.ErrInvalidToken)
+	}
+}
+
+func TestValidatePATRejectsAnExpiredToken(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	plaintext := createPAT(t, db, []string{"api"}, time.Now().Add(-time.Hour))
+
+	if _, err := auth.ValidatePAT(db, plaintext, ""); err != auth.ErrInvalidToken {
+		t.Errorf("ValidatePAT(expired) = %v, want %v", err, auth.ErrInvalidToken)
+	}
+}
+
+func TestValidatePATRejectsAMissingScope(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	plaintext := createPAT(t, db, []string{"api"}, time.Now().Add(time.Hour))
+
+	if _, err := auth.ValidatePAT(db, plaintext, "admin"); err != auth.ErrInvalidToken {
+		t.Errorf("ValidatePAT(wrong scope) = %v, want %v", err, auth.ErrInvalidToken)
+	}
+}
@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// refreshTokenBytes is how many random bytes a refresh token carries (hex-
+// encoded to 64 characters); that is more than a personal access token's 24.
+const refreshTokenBytes = 32
+
+// GenerateRefreshToken returns a new random plaintext refresh token and the
+// hash that should be persisted in its place, mirroring GeneratePAT. The
+// plaintext is never stored anywhere and can't be recovered once returned.
+func GenerateRefreshToken() (plaintext, hash string, err error) {
+	raw := make([]byte, refreshTokenBytes)
+	if _, err = rand.Read(raw); err != nil {
+		return "", "", err
+	}
+	plaintext = hex.EncodeToString(raw)
+	return plaintext, HashRefreshToken(plaintext), nil
+}
+
+// HashRefreshToken returns the SHA-256 hash of a plaintext refresh token,
+// hex-encoded.
+func HashRefreshToken(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,40 +1,247 @@
 package config
 
 import (
+	"log"
 	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
 )
 
+// BankProviderConfig holds the OAuth2 client registration for one bank/card
+// data provider (keyed by Account.Provider, e.g. "bank_leumi").
+type BankProviderConfig struct {
+	ClientID        string
+	ClientSecret    string
+	AuthURL         string
+	TokenURL        string
+	RedirectURL     string
+	TransactionsURL string
+	Scopes          []string
+}
+
+// OAuthProviderConfig holds the OAuth2 client registration for one login
+// provider (keyed by name, e.g. "google", "github"). The authorization,
+// token, and userinfo endpoints are well-known for these providers and live
+// in the auth.LoginProvider implementations instead.
+type OAuthProviderConfig struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+}
+
 type Config struct {
 	Port        string
 	Environment string
 	JWTSecret   string
-	DBHost      string
-	DBPort      string
-	DBUser      string
-	DBPassword  string
-	DBName      string
-	LogLevel    string
+	// ProviderTokenKey is the AES-256 key models.EncryptedString uses to
+	// encrypt provider access/refresh tokens at rest; must be exactly 32
+	// bytes. ProviderStateSecret and LoginStateSecret sign the OAuth2 state
+	// cookies ProviderConnect/OAuthLoginStart hand out, so a callback can't
+	// be forged or replayed against another session.
+	ProviderTokenKey    string
+	ProviderStateSecret string
+	LoginStateSecret    string
+	DBDriver            string // "sqlite" or "postgres"
+	DatabasePath        string // sqlite file path, used when DBDriver == "sqlite"
+	DBHost              string
+	DBPort              string
+	DBUser              string
+	DBPassword          string
+	DBName              string
+	LogLevel            string
+
+	BankProviders  map[string]BankProviderConfig
+	OAuthProviders map[string]OAuthProviderConfig
+
+	// TrustedProxies lists the remote addresses (as seen on
+	// http.Request.RemoteAddr, no port) allowed to set X-Forwarded-For --
+	// middleware.RateLimit's by-IP keying trusts that header only from
+	// these, so a direct client can't spoof it to dodge its own bucket.
+	TrustedProxies []string
+	// CORSAllowedOrigins lists the origins middleware.CORS reflects back in
+	// Access-Control-Allow-Origin, or ["*"] to allow any origin.
+	CORSAllowedOrigins []string
+	// RateLimits configures middleware.RateLimit per route, keyed by the
+	// short name each route passes to it (e.g. "auth", "api"), not by path.
+	RateLimits map[string]RateLimit
+
+	// Ingestion is the fetcher/normalizer tuning snapshot as of the last
+	// Load/reload. Long-lived readers (the fetcher loop) should go through
+	// an IngestionConfigStore instead, so they pick up config.yaml edits
+	// without a restart.
+	Ingestion *IngestionConfig
 }
 
+// Load builds the Config by layering, lowest precedence first: in-code
+// defaults, config.yaml (if present), environment variables, then CLI
+// flags. It calls log.Fatal on a malformed config.yaml, matching the rest
+// of this package's fail-fast style.
 func Load() *Config {
+	v := viper.GetViper()
+	setDefaults(v)
+	bindEnv(v)
+	bindFlags(v)
+
+	v.SetConfigName("config")
+	v.SetConfigType("yaml")
+	v.AddConfigPath(".")
+	if err := v.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			log.Fatalf("Failed to read config.yaml: %v", err)
+		}
+	}
+
 	return &Config{
-		Port:        getEnv("PORT", "8080"),
-		Environment: getEnv("ENVIRONMENT", "development"),
-		JWTSecret:   getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
-		DBHost:      getEnv("DB_HOST", "localhost"),
-		DBPort:      getEnv("DB_PORT", "5432"),
-		DBUser:      getEnv("DB_USER", "postgres"),
-		DBPassword:  getEnv("DB_PASSWORD", ""),
-		DBName:      getEnv("DB_NAME", "selflearning"),
-		LogLevel:    getEnv("LOG_LEVEL", "info"),
+		Port:                v.GetString("port"),
+		Environment:         v.GetString("environment"),
+		JWTSecret:           v.GetString("jwt_secret"),
+		ProviderTokenKey:    v.GetString("provider_token_key"),
+		ProviderStateSecret: v.GetString("provider_state_secret"),
+		LoginStateSecret:    v.GetString("login_state_secret"),
+		DBDriver:            v.GetString("db_driver"),
+		DatabasePath:        v.GetString("db_path"),
+		DBHost:              v.GetString("db_host"),
+		DBPort:              v.GetString("db_port"),
+		DBUser:              v.GetString("db_user"),
+		DBPassword:          v.GetString("db_password"),
+		DBName:              v.GetString("db_name"),
+		LogLevel:            v.GetString("log_level"),
+
+		BankProviders:      loadBankProviders(v),
+		OAuthProviders:     loadOAuthProviders(v),
+		TrustedProxies:     v.GetStringSlice("rate_limit.trusted_proxies"),
+		RateLimits:         loadRateLimits(v),
+		CORSAllowedOrigins: v.GetStringSlice("cors.allowed_origins"),
+		Ingestion:          loadIngestionConfig(v),
+	}
+}
+
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("port", "8080")
+	v.SetDefault("environment", "development")
+	v.SetDefault("jwt_secret", "your-secret-key-change-in-production")
+	v.SetDefault("provider_token_key", "change-me-32-byte-aes-key-please")
+	v.SetDefault("provider_state_secret", "change-me-provider-state-secret")
+	v.SetDefault("login_state_secret", "change-me-login-state-secret")
+	v.SetDefault("db_driver", "sqlite")
+	v.SetDefault("db_path", "riseapp.db")
+	v.SetDefault("db_host", "localhost")
+	v.SetDefault("db_port", "5432")
+	v.SetDefault("db_user", "postgres")
+	v.SetDefault("db_password", "")
+	v.SetDefault("db_name", "selflearning")
+	v.SetDefault("log_level", "info")
+
+	setIngestionDefaults(v)
+	setRateLimitDefaults(v)
+
+	v.SetDefault("cors.allowed_origins", []string{"*"})
+}
+
+// bindEnv keeps the original SCREAMING_SNAKE env var names working even
+// though config.yaml and viper's internal keys are lower_snake.
+func bindEnv(v *viper.Viper) {
+	for _, key := range []string{
+		"port", "environment", "jwt_secret",
+		"provider_token_key", "provider_state_secret", "login_state_secret",
+		"db_driver", "db_path", "db_host", "db_port", "db_user", "db_password", "db_name",
+		"log_level",
+		"bank_leumi.client_id", "bank_leumi.client_secret", "bank_leumi.auth_url",
+		"bank_leumi.token_url", "bank_leumi.redirect_url", "bank_leumi.transactions_url",
+		"visa.client_id", "visa.client_secret", "visa.auth_url",
+		"visa.token_url", "visa.redirect_url", "visa.transactions_url",
+		"oauth_google.client_id", "oauth_google.client_secret", "oauth_google.redirect_url",
+		"oauth_github.client_id", "oauth_github.client_secret", "oauth_github.redirect_url",
+	} {
+		envName := strings.ToUpper(strings.NewReplacer(".", "_").Replace(key))
+		_ = v.BindEnv(key, envName)
 	}
 }
 
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// bindFlags lets ops override a tuning knob for one run without touching
+// config.yaml, e.g. `go run ./cmd/server --ingestion-batch-size=1` while
+// chasing down a provider incident.
+func bindFlags(v *viper.Viper) {
+	flags := pflag.NewFlagSet("config", pflag.ContinueOnError)
+	flags.String("port", "", "HTTP port to listen on")
+	flags.Duration("ingestion-tick-interval", 0, "fetcher poll interval")
+	flags.Int("ingestion-batch-size", 0, "accounts stolen per fetcher tick")
+	flags.ParseErrorsWhitelist.UnknownFlags = true
+	_ = flags.Parse(os.Args[1:])
+
+	_ = v.BindPFlag("port", flags.Lookup("port"))
+	_ = v.BindPFlag("ingestion.tick_interval", flags.Lookup("ingestion-tick-interval"))
+	_ = v.BindPFlag("ingestion.batch_size", flags.Lookup("ingestion-batch-size"))
+}
+
+// loadBankProviders wires up the OAuth2 client registrations for the
+// bank/card providers the ingestion pipeline knows how to talk to. New
+// providers get a new entry here (and a matching config.yaml/env block).
+func loadBankProviders(v *viper.Viper) map[string]BankProviderConfig {
+	return map[string]BankProviderConfig{
+		"bank_leumi": {
+			ClientID:        v.GetString("bank_leumi.client_id"),
+			ClientSecret:    v.GetString("bank_leumi.client_secret"),
+			AuthURL:         v.GetString("bank_leumi.auth_url"),
+			TokenURL:        v.GetString("bank_leumi.token_url"),
+			RedirectURL:     v.GetString("bank_leumi.redirect_url"),
+			TransactionsURL: v.GetString("bank_leumi.transactions_url"),
+			Scopes:          []string{"accounts:read", "transactions:read"},
+		},
+		"visa": {
+			ClientID:        v.GetString("visa.client_id"),
+			ClientSecret:    v.GetString("visa.client_secret"),
+			AuthURL:         v.GetString("visa.auth_url"),
+			TokenURL:        v.GetString("visa.token_url"),
+			RedirectURL:     v.GetString("visa.redirect_url"),
+			TransactionsURL: v.GetString("visa.transactions_url"),
+			Scopes:          []string{"transactions:read"},
+		},
+	}
+}
+
+// loadOAuthProviders wires up the OAuth2 client registrations for the login
+// providers (as opposed to loadBankProviders, which is the data-fetching
+// side). New providers get a new entry here plus an implementation in
+// internal/auth.
+func loadOAuthProviders(v *viper.Viper) map[string]OAuthProviderConfig {
+	return map[string]OAuthProviderConfig{
+		"google": {
+			ClientID:     v.GetString("oauth_google.client_id"),
+			ClientSecret: v.GetString("oauth_google.client_secret"),
+			RedirectURL:  v.GetString("oauth_google.redirect_url"),
+		},
+		"github": {
+			ClientID:     v.GetString("oauth_github.client_id"),
+			ClientSecret: v.GetString("oauth_github.client_secret"),
+			RedirectURL:  v.GetString("oauth_github.redirect_url"),
+		},
+	}
+}
+
+// setRateLimitDefaults seeds a strict limit for the auth routes (blunting
+// credential stuffing against /login and /register) and a looser one for
+// general API traffic. config.yaml can override or add route names.
+func setRateLimitDefaults(v *viper.Viper) {
+	v.SetDefault("rate_limit.trusted_proxies", []string{})
+	v.SetDefault("rate_limit.routes", map[string]interface{}{
+		"auth": map[string]interface{}{"requests_per_minute": 5, "burst": 5},
+		"api":  map[string]interface{}{"requests_per_minute": 60, "burst": 60},
+	})
+}
+
+// loadRateLimits reads rate_limit.routes into the same RateLimit struct
+// loadIngestionConfig uses for per-provider limits -- the shape (requests
+// per minute plus a burst) is identical, just keyed by route name here
+// instead of by Account.Provider.
+func loadRateLimits(v *viper.Viper) map[string]RateLimit {
+	var routes map[string]RateLimit
+	if err := v.UnmarshalKey("rate_limit.routes", &routes); err != nil {
+		log.Fatalf("Failed to parse rate_limit.routes: %v", err)
 	}
-	return defaultValue
+	return routes
 }
 
 func (c *Config) IsDevelopment() bool {
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// RateLimit is a requests-per-minute budget plus a burst. It caps how hard
+// the fetcher is allowed to hit one provider, and (via Config.RateLimits) how
+// hard a client may hit one HTTP route group.
+type RateLimit struct {
+	RequestsPerMinute int `mapstructure:"requests_per_minute"`
+	Burst             int `mapstructure:"burst"`
+}
+
+// IngestionConfig holds every fetcher/normalizer tuning knob that used to be
+// hard-coded in internal/ingestion. It's read fresh at the top of each
+// fetcher loop iteration via IngestionConfigStore, so lowering BatchSize or
+// widening TickInterval in config.yaml takes effect without a restart.
+type IngestionConfig struct {
+	// TickInterval is how often the fetcher polls for work.
+	TickInterval time.Duration
+	// IdleRescanAfter is how long an IDLE account sits before it's eligible
+	// for a scheduled re-sync.
+	IdleRescanAfter time.Duration
+	// ZombieTimeout is how long an account can sit in SYNCING before the
+	// fetcher assumes the worker that claimed it died and steals it back.
+	ZombieTimeout time.Duration
+	// BatchSize is how many accounts stealWork claims per tick.
+	BatchSize int
+	// InitialCursorLookback is how far back a brand-new account's first
+	// sync reaches.
+	InitialCursorLookback time.Duration
+	// QueueBufferSize sizes the channel between the fetcher and normalizer.
+	QueueBufferSize int
+	// ProviderRateLimits caps requests per provider, keyed by
+	// Account.Provider (e.g. "bank_leumi").
+	ProviderRateLimits map[string]RateLimit
+}
+
+func setIngestionDefaults(v *viper.Viper) {
+	v.SetDefault("ingestion.tick_interval", "5s")
+	v.SetDefault("ingestion.idle_rescan_after", "6h")
+	v.SetDefault("ingestion.zombie_timeout", "1h")
+	v.SetDefault("ingestion.batch_size", 10)
+	v.SetDefault("ingestion.initial_cursor_lookback", "2160h") // 90 days
+	v.SetDefault("ingestion.queue_buffer_size", 100)
+	v.SetDefault("ingestion.provider_rate_limits", map[string]interface{}{})
+}
+
+func loadIngestionConfig(v *viper.Viper) *IngestionConfig {
+	var rateLimits map[string]RateLimit
+	if err := v.UnmarshalKey("ingestion.provider_rate_limits", &rateLimits); err != nil {
+		log.Fatalf("Failed to parse ingestion.provider_rate_limits: %v", err)
+	}
+
+	return &IngestionConfig{
+		TickInterval:          v.GetDuration("ingestion.tick_interval"),
+		IdleRescanAfter:       v.GetDuration("ingestion.idle_rescan_after"),
+		ZombieTimeout:         v.GetDuration("ingestion.zombie_timeout"),
+		BatchSize:             v.GetInt("ingestion.batch_size"),
+		InitialCursorLookback: v.GetDuration("ingestion.initial_cursor_lookback"),
+		QueueBufferSize:       v.GetInt("ingestion.queue_buffer_size"),
+		ProviderRateLimits:    rateLimits,
+	}
+}
+
+// IngestionConfigStore is the hot-reloadable handle the fetcher loop reads
+// from. NewIngestionConfigStore wires viper's WatchConfig so editing
+// config.yaml's ingestion.* keys while the server is running atomically
+// swaps the value Load returns, without restarting the process.
+type IngestionConfigStore struct {
+	current atomic.Pointer[IngestionConfig]
+}
+
+// NewIngestionConfigStore seeds the store from cfg.Ingestion and starts
+// watching config.yaml for edits to the ingestion.* keys.
+func NewIngestionConfigStore(cfg *Config) *IngestionConfigStore {
+	s := &IngestionConfigStore{}
+	s.current.Store(cfg.Ingestion)
+
+	v := viper.GetViper()
+	v.OnConfigChange(func(_ fsnotify.Event) {
+		next := loadIngestionConfig(v)
+		s.current.Store(next)
+		log.Printf("Ingestion config reloaded: tick_interval=%s batch_size=%d",
+			next.TickInterval, next.BatchSize)
+	})
+	v.WatchConfig()
+
+	return s
+}
+
+// Load returns the current ingestion tuning, reflecting the latest
+// config.yaml reload if any.
+func (s *IngestionConfigStore) Load() *IngestionConfig {
+	return s.current.Load()
+}
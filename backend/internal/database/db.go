@@ -1,51 +1,92 @@
 package database
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"path/filepath"
 
+	"github.com/self-learning/backend/internal/config"
 	"github.com/self-learning/backend/internal/models"
+	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
-func Connect(dbName string) *gorm.DB {
-	if dbName == "" {
-		dbName = "riseapp.db"
-	}
-
-	// 1. Ensure directory exists
-	dbDir := filepath.Dir(dbName)
-	if dbDir != "." && dbDir != "" {
-		if err := os.MkdirAll(dbDir, 0755); err != nil {
-			log.Fatalf("Failed to create database directory %s: %v", dbDir, err)
-		}
-	}
+// Connect opens a GORM connection using the driver selected by cfg.DBDriver
+// ("sqlite" or "postgres"), runs pending migrations, and returns the handle.
+func Connect(cfg *config.Config) *gorm.DB {
+	models.SetProviderTokenKey([]byte(cfg.ProviderTokenKey))
 
-	// 2. Open Connection
-	db, err := gorm.Open(sqlite.Open(dbName), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
-	})
+	db, err := openDB(cfg)
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
-	// 3. Enable WAL Mode for concurrency
-	db.Exec("PRAGMA journal_mode=WAL;")
+	// Enable WAL mode for concurrency (no-op on postgres)
+	if cfg.DBDriver != "postgres" {
+		db.Exec("PRAGMA journal_mode=WAL;")
+	}
 
-	// 4. Auto Migrate
+	// Run pending schema migrations (see internal/database/migrations) on
+	// this connection, then reopen a fresh one for app use. migrate's
+	// sqlite3 and postgres drivers both close whatever *sql.DB they're
+	// handed once the run finishes, so continuing to use db here would fail
+	// every later query with "database is closed".
 	log.Println("Running Database Migrations...")
-	err = db.AutoMigrate(
-		&models.User{},
-		&models.Account{},
-		&models.Transaction{},
-	)
+	migrationSQLDB, err := db.DB()
 	if err != nil {
+		log.Fatal("Failed to get underlying sql.DB:", err)
+	}
+	if err := Migrate(migrationSQLDB, cfg.DBDriver); err != nil {
 		log.Fatal("Migration failed:", err)
 	}
 	log.Println("Database Migration Complete!")
 
+	db, err = openDB(cfg)
+	if err != nil {
+		log.Fatal("Failed to reopen database after migration:", err)
+	}
+
+	if err := seedDemoUsers(db); err != nil {
+		log.Fatal("Failed to seed demo users:", err)
+	}
+
 	return db
 }
+
+// openDB opens a GORM connection using the driver selected by cfg.DBDriver,
+// creating the sqlite database's parent directory first if needed.
+func openDB(cfg *config.Config) (*gorm.DB, error) {
+	gormCfg := &gorm.Config{
+		Logger:         logger.Default.LogMode(logger.Info),
+		TranslateError: true,
+	}
+
+	if cfg.DBDriver == "postgres" {
+		return gorm.Open(postgres.Open(postgresDSN(cfg)), gormCfg)
+	}
+
+	dbName := cfg.DatabasePath
+	if dbName == "" {
+		dbName = "riseapp.db"
+	}
+
+	dbDir := filepath.Dir(dbName)
+	if dbDir != "." && dbDir != "" {
+		if mkErr := os.MkdirAll(dbDir, 0755); mkErr != nil {
+			return nil, fmt.Errorf("failed to create database directory %s: %w", dbDir, mkErr)
+		}
+	}
+
+	// _txlock=immediate makes every transaction BEGIN IMMEDIATE instead of
+	// BEGIN DEFERRED, so stealWork's read-then-update acquires the write
+	// lock up front rather than racing another connection for it.
+	return gorm.Open(sqlite.Open(dbName+"?_txlock=immediate"), gormCfg)
+}
+
+func postgresDSN(cfg *config.Config) string {
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBPassword, cfg.DBName)
+}
@@ -0,0 +1,70 @@
+package database
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+
+	"github.com/golang-migrate/migrate/v4"
+	migratedb "github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/database/sqlite3"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// NewMigrator builds a *migrate.Migrate bound to the embedded SQL files and
+// the given connection. driverName selects the migration driver ("postgres"
+// or "sqlite", defaulting to sqlite). Closing the returned *migrate.Migrate
+// also closes sqlDB: both migration drivers close the connection they were
+// handed.
+func NewMigrator(sqlDB *sql.DB, driverName string) (*migrate.Migrate, error) {
+	source, err := iofs.New(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load embedded migrations: %w", err)
+	}
+
+	var (
+		migrationDriver migratedb.Driver
+		name            string
+	)
+	switch driverName {
+	case "postgres":
+		migrationDriver, err = postgres.WithInstance(sqlDB, &postgres.Config{})
+		name = "postgres"
+	default:
+		migrationDriver, err = sqlite3.WithInstance(sqlDB, &sqlite3.Config{})
+		name = "sqlite3"
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to init %s migration driver: %w", driverName, err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, name, migrationDriver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init migrator: %w", err)
+	}
+
+	return m, nil
+}
+
+// Migrate runs every pending migration against sqlDB, acquiring the
+// migration driver's lock for the duration of the run (a Postgres advisory
+// lock on Postgres) so concurrent boots of the process don't race on the
+// schema.
+func Migrate(sqlDB *sql.DB, driverName string) error {
+	m, err := NewMigrator(sqlDB, driverName)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	return nil
+}
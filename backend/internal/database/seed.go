@@ -0,0 +1,63 @@
+package database
+
+import (
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/self-learning/backend/internal/models"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// seedBcryptCost matches the cost Register hashes new signups at, so a demo
+// account is no weaker than one a real user would create.
+const seedBcryptCost = 12
+
+// demoUsers are the accounts that used to live in the hardcoded Basic Auth
+// validUsers map. They're seeded once, on an empty users table, so
+// integrations built against those credentials keep working now that auth
+// is backed by the database.
+var demoUsers = []struct {
+	Username string
+	Email    string
+	Password string
+	IsAdmin  bool
+}{
+	{"admin", "admin@example.com", "password123", true},
+	{"noy", "noy@example.com", "theQueen", false},
+	{"demo", "demo@example.com", "demo123", false},
+	{"user-dave", "user-dave@example.com", "password123", false},
+}
+
+// seedDemoUsers inserts demoUsers if the users table is empty. It's a no-op
+// on every boot after the first.
+func seedDemoUsers(db *gorm.DB) error {
+	var count int64
+	if err := db.Model(&models.User{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	for _, u := range demoUsers {
+		hash, err := bcrypt.GenerateFromPassword([]byte(u.Password), seedBcryptCost)
+		if err != nil {
+			return err
+		}
+		email := u.Email
+		user := &models.User{
+			ID:           uuid.New().String(),
+			Username:     u.Username,
+			Email:        &email,
+			PasswordHash: string(hash),
+			IsAdmin:      u.IsAdmin,
+		}
+		if err := db.Create(user).Error; err != nil {
+			return err
+		}
+	}
+
+	log.Printf("Seeded %d demo users", len(demoUsers))
+	return nil
+}
@@ -17,7 +17,7 @@ func (h *Handler) GetUserAccounts(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get accounts from service
-	accounts, err := h.accountService.GetUserAccounts(user.Username)
+	accounts, err := h.accountService.GetUserAccounts(user.UserID)
 	if err != nil {
 		// Handle specific error types
 		if errors.Is(err, services.ErrNotFound) {
@@ -30,4 +30,4 @@ func (h *Handler) GetUserAccounts(w http.ResponseWriter, r *http.Request) {
 
 	// Return accounts directly (best practice for simple lists)
 	respondOK(w, accounts)
-}
\ No newline at end of file
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/self-learning/backend/internal/models"
+	"github.com/self-learning/backend/internal/services"
+)
+
+// Login validates username/password against the users table and, on
+// success, returns an access/refresh token pair. The access token is a
+// short-lived bearer token for /api/v1 requests; the refresh token is
+// traded at POST /api/v1/auth/refresh for a new pair once it expires.
+func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
+	var req models.LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondBadRequest(w, "Invalid request body")
+		return
+	}
+	if req.Username == "" || req.Password == "" {
+		respondBadRequest(w, "username and password are required")
+		return
+	}
+
+	user, token, refreshToken, err := h.authService.Login(req.Username, req.Password)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCredentials) {
+			respondUnauthorized(w, "Invalid username or password")
+			return
+		}
+		respondInternalError(w, "Failed to log in")
+		return
+	}
+
+	respondOK(w, models.LoginResponse{
+		User: models.UserResponse{
+			ID:       user.ID,
+			Username: user.Username,
+			Email:    user.EmailOrEmpty(),
+		},
+		Token:        token,
+		RefreshToken: refreshToken,
+	})
+}
+
+// Register creates a new password-login User and returns it (without a
+// token pair -- the caller still has to hit /login, matching how the OAuth
+// flows end in a token pair only once a login actually happens).
+func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
+	var req models.RegisterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondBadRequest(w, "Invalid request body")
+		return
+	}
+	if len(req.Username) < 3 || len(req.Username) > 50 || req.Email == "" || len(req.Password) < 6 {
+		respondBadRequest(w, "username (3-50 chars), email, and password (6+ chars) are required")
+		return
+	}
+
+	user, err := h.authService.Register(req.Username, req.Email, req.Password)
+	if err != nil {
+		if errors.Is(err, services.ErrUserExists) {
+			respondConflict(w, "Username or email already taken")
+			return
+		}
+		respondInternalError(w, "Failed to register")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, models.UserResponse{
+		ID:       user.ID,
+		Username: user.Username,
+		Email:    user.EmailOrEmpty(),
+	})
+}
+
+// Refresh rotates a refresh token: the presented one is revoked and a new
+// access/refresh pair is issued in its place. Reuse of an already-rotated
+// token is rejected and revokes the rest of that user's active tokens (see
+// AuthService.Refresh), so the caller always sees the same 401 either way.
+func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req models.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondBadRequest(w, "Invalid request body")
+		return
+	}
+	if req.RefreshToken == "" {
+		respondBadRequest(w, "refresh_token is required")
+		return
+	}
+
+	token, refreshToken, err := h.authService.Refresh(req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidRefreshToken) {
+			respondUnauthorized(w, "Invalid or expired refresh token")
+			return
+		}
+		respondInternalError(w, "Failed to refresh token")
+		return
+	}
+
+	respondOK(w, models.RefreshResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+	})
+}
+
+// Logout revokes the presented refresh token so it can no longer be
+// redeemed for a new token pair.
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	var req models.LogoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondBadRequest(w, "Invalid request body")
+		return
+	}
+	if req.RefreshToken == "" {
+		respondBadRequest(w, "refresh_token is required")
+		return
+	}
+
+	if err := h.authService.Logout(req.RefreshToken); err != nil {
+		if errors.Is(err, services.ErrInvalidRefreshToken) {
+			respondUnauthorized(w, "Invalid or expired refresh token")
+			return
+		}
+		respondInternalError(w, "Failed to log out")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/self-learning/backend/internal/services"
+)
+
+// ListDLQ returns every ingestion job that's been dead-lettered or is
+// waiting on a retry, for operators inspecting stuck syncs.
+func (h *Handler) ListDLQ(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.dlqService.List()
+	if err != nil {
+		respondInternalError(w, "Failed to list DLQ jobs")
+		return
+	}
+
+	respondOK(w, rows)
+}
+
+// ReplayDLQ clears a dead-lettered job and schedules it for an immediate
+// retry, regardless of how many attempts it already burned through.
+func (h *Handler) ReplayDLQ(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	if err := h.dlqService.Replay(id); err != nil {
+		if errors.Is(err, services.ErrDLQJobNotFound) {
+			respondNotFound(w, "DLQ job not found")
+			return
+		}
+		respondInternalError(w, "Failed to replay DLQ job")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -3,20 +3,49 @@ package handlers
 import (
 	"time"
 
+	"github.com/self-learning/backend/internal/auth"
+	"github.com/self-learning/backend/internal/config"
+	"github.com/self-learning/backend/internal/providers"
 	"github.com/self-learning/backend/internal/services"
 )
 
 type Handler struct {
-	authService    *services.AuthService
-	accountService *services.AccountService
-	startTime      time.Time
+	authService     *services.AuthService
+	accountService  *services.AccountService
+	providerService *services.ProviderService
+	patService      *services.PATService
+	dlqService      *services.DLQService
+	providers       map[string]providers.Provider
+	loginProviders  map[string]auth.LoginProvider
+	startTime       time.Time
+
+	// providerStateSecret/loginStateSecret sign the OAuth2 state cookies
+	// ProviderConnect/OAuthLoginStart hand out, so a callback can't be
+	// forged or replayed against another session.
+	providerStateSecret []byte
+	loginStateSecret    []byte
 }
 
 func New(
+	cfg *config.Config,
+	authService *services.AuthService,
 	accountService *services.AccountService,
+	providerService *services.ProviderService,
+	patService *services.PATService,
+	dlqService *services.DLQService,
+	providerRegistry map[string]providers.Provider,
+	loginProviderRegistry map[string]auth.LoginProvider,
 ) *Handler {
 	return &Handler{
-		accountService: accountService,
-		startTime:      time.Now(),
+		authService:         authService,
+		accountService:      accountService,
+		providerService:     providerService,
+		patService:          patService,
+		dlqService:          dlqService,
+		providers:           providerRegistry,
+		loginProviders:      loginProviderRegistry,
+		startTime:           time.Now(),
+		providerStateSecret: []byte(cfg.ProviderStateSecret),
+		loginStateSecret:    []byte(cfg.LoginStateSecret),
 	}
 }
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/self-learning/backend/internal/models"
+)
+
+const loginStateCookie = "login_oauth_state"
+
+// LoginProviders lists the configured OAuth2/OIDC login providers by name,
+// so a frontend can render a "Sign in with ..." button for each without
+// hardcoding the set.
+func (h *Handler) LoginProviders(w http.ResponseWriter, r *http.Request) {
+	names := make([]string, 0, len(h.loginProviders))
+	for name := range h.loginProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	respondOK(w, models.LoginProvidersResponse{Providers: names})
+}
+
+// OAuthLoginStart redirects to the named login provider's consent screen,
+// carrying a signed state cookie the callback uses to guard against CSRF.
+// Unlike ProviderConnect, there's no authenticated user yet at this point.
+func (h *Handler) OAuthLoginStart(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["provider"]
+	provider, ok := h.loginProviders[name]
+	if !ok {
+		respondNotFound(w, "Unknown login provider")
+		return
+	}
+
+	state, err := h.signLoginState(name)
+	if err != nil {
+		respondInternalError(w, "Failed to start login")
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     loginStateCookie,
+		Value:    state,
+		Path:     "/api/v1/auth/oauth/" + name + "/callback",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+		Expires:  time.Now().Add(10 * time.Minute),
+	})
+
+	http.Redirect(w, r, provider.AuthURL(state), http.StatusFound)
+}
+
+// OAuthLoginCallback exchanges the authorization code for a token, fetches
+// the provider's userinfo, and upserts/logs in the matching User, returning
+// the same LoginResponse shape as the password flow.
+func (h *Handler) OAuthLoginCallback(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["provider"]
+	provider, ok := h.loginProviders[name]
+	if !ok {
+		respondNotFound(w, "Unknown login provider")
+		return
+	}
+
+	cookie, err := r.Cookie(loginStateCookie)
+	if err != nil {
+		respondBadRequest(w, "Missing login state cookie")
+		return
+	}
+
+	state := r.URL.Query().Get("state")
+	if state == "" || state != cookie.Value {
+		respondBadRequest(w, "State mismatch")
+		return
+	}
+
+	if err := h.verifyLoginState(name, state); err != nil {
+		respondBadRequest(w, "Invalid login state")
+		return
+	}
+
+	code := r.URL.Query().Get("code")
+	if code == "" {
+		respondBadRequest(w, "Missing authorization code")
+		return
+	}
+
+	token, err := provider.Exchange(r.Context(), code)
+	if err != nil {
+		respondBadRequest(w, "Failed to exchange authorization code")
+		return
+	}
+
+	info, err := provider.FetchUserInfo(r.Context(), token)
+	if err != nil {
+		respondInternalError(w, "Failed to fetch user info")
+		return
+	}
+
+	user, accessToken, refreshToken, err := h.authService.OAuthLogin(name, info)
+	if err != nil {
+		respondInternalError(w, "Failed to log in")
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     loginStateCookie,
+		Value:    "",
+		Path:     "/api/v1/auth/oauth/" + name + "/callback",
+		HttpOnly: true,
+		MaxAge:   -1,
+	})
+
+	respondOK(w, models.LoginResponse{
+		User: models.UserResponse{
+			ID:       user.ID,
+			Username: user.Username,
+			Email:    user.EmailOrEmpty(),
+		},
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+	})
+}
+
+// signLoginState binds a login attempt to a random nonce so it can't be
+// forged or replayed; there's no userID to bind yet, unlike provider state.
+func (h *Handler) signLoginState(provider string) (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	nonceHex := hex.EncodeToString(nonce)
+
+	payload := provider + "." + nonceHex
+	return payload + "." + h.signLoginPayload(payload), nil
+}
+
+func (h *Handler) verifyLoginState(provider, state string) error {
+	parts := strings.Split(state, ".")
+	if len(parts) != 3 || parts[0] != provider {
+		return fmt.Errorf("malformed state")
+	}
+
+	payload := strings.Join(parts[:2], ".")
+	expected := h.signLoginPayload(payload)
+	if !hmac.Equal([]byte(expected), []byte(parts[2])) {
+		return fmt.Errorf("signature mismatch")
+	}
+
+	return nil
+}
+
+func (h *Handler) signLoginPayload(payload string) string {
+	mac := hmac.New(sha256.New, h.loginStateSecret)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
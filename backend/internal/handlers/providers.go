@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/self-learning/backend/internal/middleware"
+)
+
+const providerStateCookie = "provider_oauth_state"
+
+// ProviderConnect redirects the authenticated user to the named provider's
+// OAuth2 consent screen, carrying a signed state cookie so the callback can
+// be matched back to this user.
+func (h *Handler) ProviderConnect(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	provider, ok := h.providers[name]
+	if !ok {
+		respondNotFound(w, "Unknown provider")
+		return
+	}
+
+	user, ok := middleware.RequireAuth(w, r)
+	if !ok {
+		return
+	}
+
+	state, err := h.signProviderState(name, user.UserID)
+	if err != nil {
+		respondInternalError(w, "Failed to start provider connection")
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     providerStateCookie,
+		Value:    state,
+		Path:     "/providers/" + name + "/callback",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+		Expires:  time.Now().Add(10 * time.Minute),
+	})
+
+	http.Redirect(w, r, provider.AuthURL(state), http.StatusFound)
+}
+
+// ProviderCallback exchanges the authorization code for a token, persists it,
+// and creates/updates the Account so the fetcher picks it up.
+func (h *Handler) ProviderCallback(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	provider, ok := h.providers[name]
+	if !ok {
+		respondNotFound(w, "Unknown provider")
+		return
+	}
+
+	cookie, err := r.Cookie(providerStateCookie)
+	if err != nil {
+		respondBadRequest(w, "Missing provider state cookie")
+		return
+	}
+
+	state := r.URL.Query().Get("state")
+	if state == "" || state != cookie.Value {
+		respondBadRequest(w, "State mismatch")
+		return
+	}
+
+	userID, err := h.verifyProviderState(name, state)
+	if err != nil {
+		respondBadRequest(w, "Invalid provider state")
+		return
+	}
+
+	code := r.URL.Query().Get("code")
+	if code == "" {
+		respondBadRequest(w, "Missing authorization code")
+		return
+	}
+
+	token, err := provider.Exchange(r.Context(), code)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid_grant") {
+			respondBadRequest(w, "REAUTH_REQUIRED")
+			return
+		}
+		respondInternalError(w, "Failed to exchange authorization code")
+		return
+	}
+
+	account, err := h.providerService.ConnectAccount(userID, name)
+	if err != nil {
+		respondInternalError(w, "Failed to connect account")
+		return
+	}
+
+	if err := h.providerService.SaveToken(account.ID, token); err != nil {
+		respondInternalError(w, "Failed to persist provider token")
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     providerStateCookie,
+		Value:    "",
+		Path:     "/providers/" + name + "/callback",
+		HttpOnly: true,
+		MaxAge:   -1,
+	})
+
+	respondOK(w, account)
+}
+
+// signProviderState produces a cookie/query value binding provider+userID to
+// a random nonce, so it can't be forged or replayed against another user.
+func (h *Handler) signProviderState(provider, userID string) (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	nonceHex := hex.EncodeToString(nonce)
+
+	payload := provider + "." + userID + "." + nonceHex
+	return payload + "." + h.signProviderPayload(payload), nil
+}
+
+func (h *Handler) verifyProviderState(provider, state string) (string, error) {
+	parts := strings.Split(state, ".")
+	if len(parts) != 4 || parts[0] != provider {
+		return "", fmt.Errorf("malformed state")
+	}
+
+	payload := strings.Join(parts[:3], ".")
+	expected := h.signProviderPayload(payload)
+	if !hmac.Equal([]byte(expected), []byte(parts[3])) {
+		return "", fmt.Errorf("signature mismatch")
+	}
+
+	return parts[1], nil
+}
+
+func (h *Handler) signProviderPayload(payload string) string {
+	mac := hmac.New(sha256.New, h.providerStateSecret)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
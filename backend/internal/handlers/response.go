@@ -41,6 +41,14 @@ func respondNotFound(w http.ResponseWriter, message string) {
 	respondError(w, http.StatusNotFound, "not_found", message)
 }
 
+func respondForbidden(w http.ResponseWriter, message string) {
+	respondError(w, http.StatusForbidden, "forbidden", message)
+}
+
+func respondConflict(w http.ResponseWriter, message string) {
+	respondError(w, http.StatusConflict, "conflict", message)
+}
+
 func respondInternalError(w http.ResponseWriter, message string) {
 	respondError(w, http.StatusInternalServerError, "internal_error", message)
 }
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/self-learning/backend/internal/middleware"
+	"github.com/self-learning/backend/internal/models"
+	"github.com/self-learning/backend/internal/services"
+)
+
+// defaultTokenTTL is used when CreateTokenRequest doesn't specify one.
+const defaultTokenTTL = 90 * 24 * time.Hour
+
+// CreateToken mints a personal access token for the authenticated user and
+// returns the plaintext exactly once; only its hash is ever persisted.
+func (h *Handler) CreateToken(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.RequireAuth(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.CreateTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondBadRequest(w, "Invalid request body")
+		return
+	}
+	if req.Name == "" {
+		respondBadRequest(w, "name is required")
+		return
+	}
+	if len(req.Scopes) == 0 {
+		respondBadRequest(w, "scopes is required")
+		return
+	}
+
+	ttl := defaultTokenTTL
+	if req.TTLSeconds > 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+
+	plaintext, pat, err := h.patService.Mint(user.UserID, req.Name, req.Scopes, ttl)
+	if err != nil {
+		if errors.Is(err, services.ErrInsufficientScope) {
+			respondForbidden(w, "Not authorized to mint a token with the requested scope")
+			return
+		}
+		respondInternalError(w, "Failed to create token")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, models.CreateTokenResponse{
+		ID:        pat.ID,
+		Token:     plaintext,
+		Name:      pat.Name,
+		Scopes:    pat.Scopes,
+		ExpiresAt: pat.ExpiresAt,
+	})
+}
+
+// RevokeToken deletes a personal access token belonging to the authenticated
+// user.
+func (h *Handler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.RequireAuth(w, r)
+	if !ok {
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	if err := h.patService.Revoke(user.UserID, id); err != nil {
+		if errors.Is(err, services.ErrTokenNotFound) {
+			respondNotFound(w, "Token not found")
+			return
+		}
+		respondInternalError(w, "Failed to revoke token")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,61 +1,137 @@
 package ingestion
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/self-learning/backend/internal/config"
 	"github.com/self-learning/backend/internal/models"
+	"github.com/self-learning/backend/internal/providers"
+	"golang.org/x/time/rate"
 	"gorm.io/gorm"
 )
 
+// tokenRefreshThreshold controls how far ahead of expiry a provider token is
+// proactively refreshed.
+const tokenRefreshThreshold = 5 * time.Minute
+
 // StartFetcherLoop is the main entry point for the extraction process.
-// It continuously looks for accounts that need syncing.
-func StartFetcherLoop(db *gorm.DB, queue chan<- SyncJob) {
+// It continuously looks for accounts that need syncing. driverName selects
+// the row-locking strategy used by stealWork ("postgres" or "sqlite").
+// registry supplies the Provider for each Account.Provider value. cfgStore
+// is read at the top of every tick, so editing config.yaml's ingestion.*
+// knobs (tick_interval, batch_size, ...) takes effect without a restart. The
+// loop exits as soon as ctx is cancelled, so callers can shut it down
+// cleanly.
+func StartFetcherLoop(ctx context.Context, db *gorm.DB, driverName string, registry map[string]providers.Provider, queue chan<- SyncJob, cfgStore *config.IngestionConfigStore) {
 	log.Println("🚜 Ingestion Engine: Fetcher Loop Started")
 
-	ticker := time.NewTicker(5 * time.Second)
-
-	for range ticker.C {
-		// 1. Find and Lock work (The "Steal")
-		accounts, err := stealWork(db)
-		if err != nil {
-			log.Printf("❌ Fetcher: Error stealing work: %v", err)
-			continue
-		}
-
-		if len(accounts) == 0 {
-			// No work found, sleep until next tick
-			continue
-		}
-
-		log.Printf("🚜 Fetcher: Picked up %d accounts", len(accounts))
-
-		// 2. Process each account
-		for _, acc := range accounts {
-			processAccount(db, acc, queue)
+	interval := cfgStore.Load().TickInterval
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("🚜 Ingestion Engine: Fetcher Loop Stopped")
+			return
+		case <-ticker.C:
+			cfg := cfgStore.Load()
+			if cfg.TickInterval != interval {
+				interval = cfg.TickInterval
+				ticker.Reset(interval)
+			}
+
+			// 1. Find and Lock work (The "Steal")
+			accounts, err := stealWork(db, driverName, cfg)
+			if err != nil {
+				log.Printf("❌ Fetcher: Error stealing work: %v", err)
+				continue
+			}
+
+			if len(accounts) == 0 {
+				// No work found, sleep until next tick
+				continue
+			}
+
+			log.Printf("🚜 Fetcher: Picked up %d accounts", len(accounts))
+
+			// 2. Process each account
+			for _, acc := range accounts {
+				processAccount(ctx, db, acc, registry, queue, cfg)
+			}
 		}
 	}
 }
 
 // stealWork atomically finds eligible accounts and marks them as SYNCING.
-func stealWork(db *gorm.DB) ([]models.Account, error) {
+// Eligibility is the same on every driver:
+//  1. User requested (PENDING_SYNC)
+//  2. Scheduled (IDLE + older than cfg.IdleRescanAfter)
+//  3. Zombie Recovery (SYNCING + older than cfg.ZombieTimeout)
+//
+// These are exactly the predicates the (sync_status, priority),
+// (sync_status, last_updated_at) and (sync_status, last_sync_attempt)
+// indexes (see internal/database/migrations/0004_add_sync_columns.up.sql)
+// keep index-only, so the eligibility scan never falls back to a table scan.
+func stealWork(db *gorm.DB, driverName string, cfg *config.IngestionConfig) ([]models.Account, error) {
+	if driverName == "postgres" {
+		return stealWorkPostgres(db, cfg)
+	}
+	return stealWorkSQLite(db, cfg)
+}
+
+// stealWorkPostgres uses a single UPDATE ... FROM (... FOR UPDATE SKIP LOCKED)
+// statement so the select-then-lock happens atomically in one round trip and
+// concurrent fetchers never contend for the same rows.
+func stealWorkPostgres(db *gorm.DB, cfg *config.IngestionConfig) ([]models.Account, error) {
+	now := time.Now()
+	idleCutoff := now.Add(-cfg.IdleRescanAfter)
+	zombieCutoff := now.Add(-cfg.ZombieTimeout)
+
 	var accounts []models.Account
+	err := db.Raw(`
+		UPDATE accounts
+		SET sync_status = ?, last_sync_attempt = ?
+		FROM (
+			SELECT id FROM accounts
+			WHERE sync_status = ?
+			   OR (sync_status = ? AND last_updated_at < ?)
+			   OR (sync_status = ? AND last_sync_attempt < ?)
+			ORDER BY priority DESC
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		) sub
+		WHERE accounts.id = sub.id
+		RETURNING accounts.*`,
+		models.StatusSyncing, now,
+		models.StatusPendingSync,
+		models.StatusIdle, idleCutoff,
+		models.StatusSyncing, zombieCutoff,
+		cfg.BatchSize,
+	).Scan(&accounts).Error
 
-	// Transaction ensures we don't pick up the same row twice in race conditions
-	err := db.Transaction(func(tx *gorm.DB) error {
+	return accounts, err
+}
 
-		// A. Define the eligibility criteria
-		// 1. User requested (PENDING_SYNC)
-		// 2. Scheduled (IDLE + >6 hours old)
-		// 3. Zombie Recovery (SYNCING + >1 hour old)
+// stealWorkSQLite mirrors stealWorkPostgres with a read-then-update pair.
+// The connection's DSN sets _txlock=immediate (see database.Connect), so this
+// transaction opens with BEGIN IMMEDIATE and takes the write lock up front
+// instead of racing another connection for it after the SELECT.
+func stealWorkSQLite(db *gorm.DB, cfg *config.IngestionConfig) ([]models.Account, error) {
+	var accounts []models.Account
 
+	err := db.Transaction(func(tx *gorm.DB) error {
 		now := time.Now()
-		sixHoursAgo := now.Add(-6 * time.Hour)
-		oneHourAgo := now.Add(-1 * time.Hour)
+		idleCutoff := now.Add(-cfg.IdleRescanAfter)
+		zombieCutoff := now.Add(-cfg.ZombieTimeout)
 
 		var eligibleIDs []string
 
@@ -63,11 +139,11 @@ func stealWork(db *gorm.DB) ([]models.Account, error) {
 		// (This is safer in GORM/SQLite than complex UPDATE...FROM syntax)
 		err := tx.Model(&models.Account{}).
 			Select("id").
-			Where("sync_status = ?", models.StatusPendingSync).                                // Priority 1
-			Or("sync_status = ? AND last_updated_at < ?", models.StatusIdle, sixHoursAgo).     // Priority 2
-			Or("sync_status = ? AND last_sync_attempt < ?", models.StatusSyncing, oneHourAgo). // Priority 3
-			Order("priority DESC").                                                            // High priority first
-			Limit(10).                                                                         // Batch size
+			Where("sync_status = ?", models.StatusPendingSync).                                  // Priority 1
+			Or("sync_status = ? AND last_updated_at < ?", models.StatusIdle, idleCutoff).        // Priority 2
+			Or("sync_status = ? AND last_sync_attempt < ?", models.StatusSyncing, zombieCutoff). // Priority 3
+			Order("priority DESC").                                                              // High priority first
+			Limit(cfg.BatchSize).                                                                // Batch size
 			Find(&eligibleIDs).Error
 
 		if err != nil {
@@ -97,27 +173,64 @@ func stealWork(db *gorm.DB) ([]models.Account, error) {
 	return accounts, err
 }
 
-func processAccount(db *gorm.DB, acc models.Account, queue chan<- SyncJob) {
+func processAccount(ctx context.Context, db *gorm.DB, acc models.Account, registry map[string]providers.Provider, queue chan<- SyncJob, cfg *config.IngestionConfig) {
 	// 1. Determine "From Date" (Cursor)
-	// Default to 90 days ago if this is the first sync
-	cursor := time.Now().Add(-90 * 24 * time.Hour)
+	// Default to cfg.InitialCursorLookback ago if this is the first sync
+	cursor := time.Now().Add(-cfg.InitialCursorLookback)
 	if acc.LastSyncedCursor != nil {
 		cursor = *acc.LastSyncedCursor
 	}
 
-	// 2. Call the Mock Bank Provider
-	// In a real app, this would use the `acc.ExternalAccountID`
+	provider, ok := registry[acc.Provider]
+	if !ok {
+		log.Printf("   ❌ No provider registered for %s (account %s)", acc.Provider, acc.ID)
+		markFailed(db, acc, "UNSUPPORTED_PROVIDER")
+		return
+	}
+
+	token, err := loadProviderToken(db, acc.ID)
+	if err != nil {
+		log.Printf("   ❌ No provider token for account %s: %v", acc.ID, err)
+		markFailed(db, acc, "REAUTH_REQUIRED")
+		return
+	}
+
+	if time.Until(token.ExpiresAt) < tokenRefreshThreshold {
+		refreshed, err := provider.Refresh(ctx, token)
+		if err != nil {
+			log.Printf("   ❌ Failed to refresh token for %s: %v", acc.ID, err)
+			markFailed(db, acc, refreshErrorCode(err))
+			return
+		}
+		token = refreshed
+		if err := saveProviderToken(db, acc.ID, token); err != nil {
+			log.Printf("   ❌ Failed to persist refreshed token for %s: %v", acc.ID, err)
+		}
+	}
+
+	// 2. Call the Bank Provider, honoring cfg.ProviderRateLimits so a noisy
+	// fetcher doesn't trip the provider's own throttling during an incident.
+	if limiter := providerLimiter(acc.Provider, cfg.ProviderRateLimits[acc.Provider]); limiter != nil {
+		if err := limiter.Wait(ctx); err != nil {
+			log.Printf("   ❌ Rate limiter wait failed for %s: %v", acc.ID, err)
+			return
+		}
+	}
+
 	log.Printf("   -> Fetching Account %s (Provider: %s) Cursor: %s",
 		acc.ID, acc.Provider, cursor.Format("2006-01-02"))
 
-	rawData, err := fetchFromBankMock(acc.Provider, cursor)
+	txns, err := provider.FetchTransactions(ctx, token, cursor)
 	if err != nil {
 		log.Printf("   ❌ Failed to fetch %s: %v", acc.ID, err)
-		// Mark failed so it doesn't get stuck in SYNCING forever (until zombie killer)
-		db.Model(&acc).Updates(map[string]interface{}{
-			"sync_status": models.StatusFailed,
-			"last_error":  err.Error(),
-		})
+		markFailed(db, acc, refreshErrorCode(err))
+		return
+	}
+
+	rawData, err := json.Marshal(txns)
+	if err != nil {
+		log.Printf("   ❌ Failed to marshal transactions for %s: %v", acc.ID, err)
+		markFailed(db, acc, err.Error())
 		return
 	}
 
@@ -149,32 +262,47 @@ func processAccount(db *gorm.DB, acc models.Account, queue chan<- SyncJob) {
 	log.Printf("   ✅ Fetched & Queued: %s", acc.ID)
 }
 
-// --- Mocks (Placeholders for real services) ---
-
-func fetchFromBankMock(provider string, from time.Time) ([]byte, error) {
-	// Simulate network latency
-	time.Sleep(200 * time.Millisecond)
-
-	// Return dummy JSON
-	mockData := []map[string]interface{}{
-		{
-			"id":       fmt.Sprintf("tx-%d", time.Now().UnixNano()),
-			"amount":   -150.00,
-			"merchant": "Mock Purchase " + provider,
-			"date":     time.Now().Format(time.RFC3339),
-			"currency": "ILS",
-			"status":   "SETTLED",
-		},
-		{
-			"id":       fmt.Sprintf("tx-%d-2", time.Now().UnixNano()),
-			"amount":   -45.00,
-			"merchant": "Coffee Shop",
-			"date":     time.Now().Format(time.RFC3339),
-			"currency": "ILS",
-			"status":   "PENDING",
-		},
-	}
-	return json.Marshal(mockData)
+// markFailed flips an account to StatusFailed with the given reason so it
+// doesn't get stuck in SYNCING forever (until the zombie killer). A reason of
+// "REAUTH_REQUIRED" tells the UI to prompt the user to reconnect the account.
+func markFailed(db *gorm.DB, acc models.Account, reason string) {
+	db.Model(&acc).Updates(map[string]interface{}{
+		"sync_status": models.StatusFailed,
+		"last_error":  reason,
+	})
+}
+
+// refreshErrorCode maps a provider error to the last_error value stored on
+// the account, surfacing "invalid_grant" as REAUTH_REQUIRED so the UI knows
+// to prompt for reconnection rather than treat it as a transient failure.
+func refreshErrorCode(err error) string {
+	if strings.Contains(err.Error(), "invalid_grant") {
+		return "REAUTH_REQUIRED"
+	}
+	return err.Error()
+}
+
+func loadProviderToken(db *gorm.DB, accountID string) (*providers.Token, error) {
+	var row models.ProviderToken
+	if err := db.Where("account_id = ?", accountID).First(&row).Error; err != nil {
+		return nil, err
+	}
+	return &providers.Token{
+		AccessToken:  string(row.AccessToken),
+		RefreshToken: string(row.RefreshToken),
+		ExpiresAt:    row.ExpiresAt,
+		Scope:        row.Scope,
+	}, nil
+}
+
+func saveProviderToken(db *gorm.DB, accountID string, token *providers.Token) error {
+	return db.Model(&models.ProviderToken{}).
+		Where("account_id = ?", accountID).
+		Updates(map[string]interface{}{
+			"access_token":  models.EncryptedString(token.AccessToken),
+			"refresh_token": models.EncryptedString(token.RefreshToken),
+			"expires_at":    token.ExpiresAt,
+		}).Error
 }
 
 func saveToLocalStorage(accountID string, data []byte) (string, error) {
@@ -191,3 +319,38 @@ func saveToLocalStorage(accountID string, data []byte) (string, error) {
 	err := os.WriteFile(fullPath, data, 0644)
 	return fullPath, err
 }
+
+// providerLimiters caches one rate.Limiter per provider so bursts are
+// tracked across ticks instead of being reset on every call.
+var providerLimiters sync.Map // map[string]*limiterEntry
+
+type limiterEntry struct {
+	limiter *rate.Limiter
+	limit   config.RateLimit
+}
+
+// providerLimiter returns the shared limiter for provider, rebuilding it if
+// cfg.ProviderRateLimits was hot-reloaded with a different limit. Returns nil
+// if no limit is configured, meaning "unlimited".
+func providerLimiter(provider string, limit config.RateLimit) *rate.Limiter {
+	if limit.RequestsPerMinute <= 0 {
+		return nil
+	}
+
+	if v, ok := providerLimiters.Load(provider); ok {
+		if entry := v.(*limiterEntry); entry.limit == limit {
+			return entry.limiter
+		}
+	}
+
+	burst := limit.Burst
+	if burst <= 0 {
+		burst = 1
+	}
+	entry := &limiterEntry{
+		limiter: rate.NewLimiter(rate.Limit(float64(limit.RequestsPerMinute)/60), burst),
+		limit:   limit,
+	}
+	providerLimiters.Store(provider, entry)
+	return entry.limiter
+}
@@ -1,23 +1,116 @@
 package ingestion
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"os"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/self-learning/backend/internal/models"
+	"github.com/self-learning/backend/internal/providers"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
-// StartNormalizerLoop listens to the queue and processes raw data into the DB.
-func StartNormalizerLoop(db *gorm.DB, queue <-chan SyncJob) {
+// maxDeliveryAttempts caps how many times a job is retried before it's
+// dead-lettered for good.
+const maxDeliveryAttempts = 5
+
+// retryBackoff is the exponential delay schedule applied after each failed
+// attempt (index 0 after the 1st failure, ..., index 3 after the 4th). A 5th
+// failure dead-letters the job instead of scheduling another retry.
+var retryBackoff = []time.Duration{
+	30 * time.Second,
+	2 * time.Minute,
+	10 * time.Minute,
+	time.Hour,
+}
+
+// retryPollInterval is how often StartRetryLoop checks failed_sync_jobs for
+// rows whose next_retry_at has come due.
+const retryPollInterval = 15 * time.Second
+
+// StartRetryLoop polls failed_sync_jobs for rows due for another attempt and
+// re-enqueues them onto queue, the same channel the Fetcher hands jobs off
+// on. It stops as soon as ctx is cancelled.
+func StartRetryLoop(ctx context.Context, db *gorm.DB, queue chan<- SyncJob) {
+	log.Println("🔁 Ingestion Engine: Retry Loop Started")
+
+	ticker := time.NewTicker(retryPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("🔁 Ingestion Engine: Retry Loop Stopped")
+			return
+		case <-ticker.C:
+			requeueDueRetries(db, queue)
+		}
+	}
+}
+
+// requeueDueRetries re-enqueues every DLQ row whose next_retry_at has
+// passed, clearing next_retry_at so it isn't picked up twice. If the retry
+// fails again, processJob's failure path re-schedules it.
+func requeueDueRetries(db *gorm.DB, queue chan<- SyncJob) {
+	var rows []models.FailedSyncJob
+	err := db.Where("dead_lettered_at IS NULL AND next_retry_at IS NOT NULL AND next_retry_at <= ?", time.Now()).
+		Find(&rows).Error
+	if err != nil {
+		log.Printf("   ❌ Retry Loop: Failed to load due retries: %v", err)
+		return
+	}
+
+	for _, row := range rows {
+		log.Printf("   🔁 Retry Loop: Re-queueing Account %s (attempt %d)", row.AccountID, row.AttemptCount+1)
+		queue <- SyncJob{
+			AccountID: row.AccountID,
+			S3Path:    row.S3Path,
+			FetchedAt: row.FetchedAt,
+		}
+		db.Model(&row).Update("next_retry_at", nil)
+	}
+}
+
+// StartNormalizerLoop listens to the queue and processes raw data into the
+// DB. On ctx cancellation it drains whatever is already buffered in queue
+// before returning, instead of waiting for the channel to be closed.
+func StartNormalizerLoop(ctx context.Context, db *gorm.DB, queue <-chan SyncJob) {
 	log.Println("🏭 Ingestion Engine: Normalizer Loop Started")
 
-	for job := range queue {
-		processJob(db, job)
+	for {
+		select {
+		case job, ok := <-queue:
+			if !ok {
+				log.Println("🏭 Ingestion Engine: Normalizer Loop Stopped (queue closed)")
+				return
+			}
+			processJob(db, job)
+		case <-ctx.Done():
+			drainQueue(db, queue)
+			log.Println("🏭 Ingestion Engine: Normalizer Loop Stopped")
+			return
+		}
+	}
+}
+
+// drainQueue processes any jobs already buffered in queue without blocking,
+// so a shutdown doesn't silently drop work that was already handed off.
+func drainQueue(db *gorm.DB, queue <-chan SyncJob) {
+	for {
+		select {
+		case job, ok := <-queue:
+			if !ok {
+				return
+			}
+			processJob(db, job)
+		default:
+			return
+		}
 	}
 }
 
@@ -28,13 +121,15 @@ func processJob(db *gorm.DB, job SyncJob) {
 	rawBytes, err := os.ReadFile(job.S3Path)
 	if err != nil {
 		log.Printf("   ❌ Normalizer: Failed to read file %s: %v", job.S3Path, err)
-		return // In a real app, send to Dead Letter Queue
+		recordJobFailure(db, job, err)
+		return
 	}
 
 	// 2. Parse (Normalize)
 	transactions, err := parseTransactions(job.AccountID, rawBytes)
 	if err != nil {
 		log.Printf("   ❌ Normalizer: Failed to parse JSON: %v", err)
+		recordJobFailure(db, job, err)
 		return
 	}
 
@@ -43,6 +138,7 @@ func processJob(db *gorm.DB, job SyncJob) {
 		err = upsertTransactions(db, transactions)
 		if err != nil {
 			log.Printf("   ❌ Normalizer: Failed to insert transactions: %v", err)
+			recordJobFailure(db, job, err)
 			return
 		}
 	}
@@ -51,40 +147,87 @@ func processJob(db *gorm.DB, job SyncJob) {
 	err = finalizeAccount(db, job)
 	if err != nil {
 		log.Printf("   ❌ Normalizer: Failed to update account status: %v", err)
+		recordJobFailure(db, job, err)
 		return
 	}
 
+	// The job made it through clean; forget any DLQ history it had.
+	db.Where("account_id = ? AND s3_path = ?", job.AccountID, job.S3Path).Delete(&models.FailedSyncJob{})
+
 	log.Printf("   ✨ Normalizer: Successfully synced %d transactions for %s", len(transactions), job.AccountID)
 }
 
+// recordJobFailure upserts job's failed_sync_jobs row, bumping AttemptCount
+// and scheduling the next retry with exponential backoff. Once AttemptCount
+// reaches maxDeliveryAttempts the row is dead-lettered instead, and the
+// account is flipped to StatusFailed so it doesn't stay stuck mid-sync
+// forever (see markFailed in fetcher.go, which this mirrors).
+func recordJobFailure(db *gorm.DB, job SyncJob, stepErr error) {
+	var row models.FailedSyncJob
+	err := db.Where("account_id = ? AND s3_path = ?", job.AccountID, job.S3Path).First(&row).Error
+
+	isNew := false
+	switch {
+	case err == nil:
+		// Existing DLQ row for this job; bump its attempt count below.
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		isNew = true
+		row = models.FailedSyncJob{
+			ID:        uuid.New().String(),
+			AccountID: job.AccountID,
+			S3Path:    job.S3Path,
+			FetchedAt: job.FetchedAt,
+		}
+	default:
+		log.Printf("   ❌ Normalizer: Failed to load DLQ row for %s: %v", job.AccountID, err)
+		return
+	}
+
+	row.AttemptCount++
+	row.LastError = stepErr.Error()
+
+	if row.AttemptCount >= maxDeliveryAttempts {
+		now := time.Now()
+		row.DeadLetteredAt = &now
+		row.NextRetryAt = nil
+	} else {
+		next := time.Now().Add(retryBackoff[row.AttemptCount-1])
+		row.NextRetryAt = &next
+	}
+
+	if isNew {
+		err = db.Create(&row).Error
+	} else {
+		err = db.Save(&row).Error
+	}
+	if err != nil {
+		log.Printf("   ❌ Normalizer: Failed to persist DLQ row for %s: %v", job.AccountID, err)
+		return
+	}
+
+	if row.DeadLetteredAt != nil {
+		markFailed(db, models.Account{ID: job.AccountID}, row.LastError)
+	}
+}
+
+// parseTransactions normalizes the raw provider JSON (see
+// internal/providers.RawTxn, written to disk by fetcher.go) into our schema.
 func parseTransactions(accountID string, data []byte) ([]models.Transaction, error) {
-	// Define a struct that matches the *Mock Bank's* JSON format (from fetcher.go)
-	type BankTxn struct {
-		ID       string  `json:"id"`
-		Amount   float64 `json:"amount"`
-		Merchant string  `json:"merchant"`
-		Date     string  `json:"date"` // RFC3339
-		Currency string  `json:"currency"`
-		Status   string  `json:"status"`
-	}
-
-	var bankTxns []BankTxn
-	if err := json.Unmarshal(data, &bankTxns); err != nil {
+	var rawTxns []providers.RawTxn
+	if err := json.Unmarshal(data, &rawTxns); err != nil {
 		return nil, err
 	}
 
 	var internalTxns []models.Transaction
-	for _, bt := range bankTxns {
-		parsedDate, _ := time.Parse(time.RFC3339, bt.Date)
-
+	for _, rt := range rawTxns {
 		t := models.Transaction{
 			ID:                    uuid.New().String(), // Generate internal UUID
 			AccountID:             accountID,
-			ProviderTransactionID: bt.ID, // Critical for Deduplication!
-			Amount:                bt.Amount,
-			MerchantName:          bt.Merchant,
-			Currency:              bt.Currency,
-			TransactionDate:       parsedDate,
+			ProviderTransactionID: rt.ID, // Critical for Deduplication!
+			Amount:                rt.Amount,
+			MerchantName:          rt.Merchant,
+			Currency:              rt.Currency,
+			TransactionDate:       rt.Date,
 			SystemInsertedAt:      time.Now(),
 		}
 		internalTxns = append(internalTxns, t)
@@ -11,14 +11,15 @@ type contextKey string
 
 const UserContextKey contextKey = "user"
 
-// GetUserFromContext extracts authenticated user from request context
-func GetUserFromContext(ctx context.Context) (*auth.User, bool) {
-	user, ok := ctx.Value(UserContextKey).(*auth.User)
+// GetUserFromContext extracts the authenticated user's claims from the
+// request context, as stamped by JWTAuth or PATAuth.
+func GetUserFromContext(ctx context.Context) (*auth.CustomClaims, bool) {
+	user, ok := ctx.Value(UserContextKey).(*auth.CustomClaims)
 	return user, ok
 }
 
 // RequireAuth helper function for handlers to get authenticated user
-func RequireAuth(w http.ResponseWriter, r *http.Request) (*auth.User, bool) {
+func RequireAuth(w http.ResponseWriter, r *http.Request) (*auth.CustomClaims, bool) {
 	user, ok := GetUserFromContext(r.Context())
 	if !ok {
 		http.Error(w, "Authentication required", http.StatusUnauthorized)
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/self-learning/backend/internal/auth"
+	"github.com/self-learning/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// PATAuth validates bearer tokens minted via POST /api/v1/tokens (prefixed
+// auth.PATPrefix), checking requiredScope and stamping LastUsedAt. Any
+// bearer token without that prefix is passed through to next untouched, so
+// chaining PATAuth ahead of JWTAuth lets a route accept either a personal
+// access token or a login-issued JWT.
+func PATAuth(db *gorm.DB, requiredScope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tokenString := bearerToken(r)
+			if !strings.HasPrefix(tokenString, auth.PATPrefix) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			pat, err := auth.ValidatePAT(db, tokenString, requiredScope)
+			if err != nil {
+				respondUnauthorized(w, "Invalid or expired personal access token")
+				return
+			}
+
+			claims, err := claimsForPAT(db, pat)
+			if err != nil {
+				respondUnauthorized(w, "Invalid or expired personal access token")
+				return
+			}
+			ctx := context.WithValue(r.Context(), UserContextKey, claims)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequirePAT behaves like PATAuth but rejects the request outright if it
+// isn't carrying a valid personal access token with requiredScope. PATAuth's
+// pass-through for non-PAT tokens exists so a subsequent JWTAuth can catch
+// them; that doesn't apply to PAT-only routes like the DLQ admin endpoints,
+// which have no equivalent interactive-login counterpart to fall back to.
+func RequirePAT(db *gorm.DB, requiredScope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tokenString := bearerToken(r)
+			if !strings.HasPrefix(tokenString, auth.PATPrefix) {
+				respondUnauthorized(w, "Missing or invalid personal access token")
+				return
+			}
+
+			pat, err := auth.ValidatePAT(db, tokenString, requiredScope)
+			if err != nil {
+				respondUnauthorized(w, "Invalid or expired personal access token")
+				return
+			}
+
+			claims, err := claimsForPAT(db, pat)
+			if err != nil {
+				respondUnauthorized(w, "Invalid or expired personal access token")
+				return
+			}
+			ctx := context.WithValue(r.Context(), UserContextKey, claims)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// claimsForPAT looks up pat's owning user so a PAT-authenticated request
+// carries the exact same claims shape a JWT-authenticated one would --
+// UserID the real user ID, Username the real username -- instead of
+// stuffing pat.UserID into both fields. Handlers downstream (GetUserAccounts,
+// CreateToken, ProviderConnect, ...) key their lookups off one or the other
+// depending on what they're touching, so the two auth paths have to agree.
+func claimsForPAT(db *gorm.DB, pat *models.PersonalAccessToken) (*auth.CustomClaims, error) {
+	var owner models.User
+	if err := db.Select("id", "username").First(&owner, "id = ?", pat.UserID).Error; err != nil {
+		return nil, err
+	}
+	return &auth.CustomClaims{UserID: owner.ID, Username: owner.Username}, nil
+}
+
+// bearerToken extracts the token from a "Bearer <token>" Authorization
+// header, returning "" if the header is missing or malformed.
+func bearerToken(r *http.Request) string {
+	authHeader := r.Header.Get("Authorization")
+	parts := strings.SplitN(authHeader, " ", 2)
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return ""
+	}
+	return parts[1]
+}
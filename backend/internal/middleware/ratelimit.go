@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"container/list"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/self-learning/backend/internal/config"
+)
+
+// keyedLimiterCacheSize caps how many distinct keys (IPs or user IDs) a
+// RateLimit instance tracks at once. Past that, the least recently touched
+// key is evicted -- an attacker cycling through IPs/accounts shouldn't be
+// able to grow this map without bound.
+const keyedLimiterCacheSize = 10_000
+
+// KeyFunc extracts the identity RateLimit should throttle on from a
+// request. ByIP and ByUser are the two modes the auth routes need; callers
+// can supply their own for anything else.
+type KeyFunc func(r *http.Request) string
+
+// ByIP keys on the caller's remote address, preferring the left-most
+// X-Forwarded-For entry when the request came from one of trustedProxies --
+// trusting that header from anyone else would let a client spoof it to
+// dodge its own bucket.
+func ByIP(trustedProxies []string) KeyFunc {
+	trusted := make(map[string]struct{}, len(trustedProxies))
+	for _, p := range trustedProxies {
+		trusted[p] = struct{}{}
+	}
+	return func(r *http.Request) string {
+		host := r.RemoteAddr
+		if h, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+			host = h
+		}
+		if _, ok := trusted[host]; ok {
+			if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+				return strings.TrimSpace(strings.Split(fwd, ",")[0])
+			}
+		}
+		return host
+	}
+}
+
+// ByUser keys on the authenticated user's ID, as stamped into context by
+// JWTAuth or PATAuth. It falls back to ByIP for requests that never
+// authenticated -- a login attempt with the wrong password still has to
+// count against something.
+func ByUser(trustedProxies []string) KeyFunc {
+	byIP := ByIP(trustedProxies)
+	return func(r *http.Request) string {
+		if claims, ok := GetUserFromContext(r.Context()); ok {
+			return "user:" + claims.UserID
+		}
+		return byIP(r)
+	}
+}
+
+// keyedLimiters is an LRU-evicted map of per-key *rate.Limiter, guarded by
+// a mutex since RateLimit's handler runs concurrently across requests.
+type keyedLimiters struct {
+	mu       sync.Mutex
+	cfg      config.RateLimit
+	entries  map[string]*list.Element
+	order    *list.List // front = most recently used
+	capacity int
+}
+
+type limiterEntry struct {
+	key     string
+	limiter *rate.Limiter
+}
+
+func newKeyedLimiters(cfg config.RateLimit, capacity int) *keyedLimiters {
+	return &keyedLimiters{
+		cfg:      cfg,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		capacity: capacity,
+	}
+}
+
+// get returns key's limiter, creating one (and evicting the least recently
+// used entry if the cache is full) on first use.
+func (k *keyedLimiters) get(key string) *rate.Limiter {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if el, ok := k.entries[key]; ok {
+		k.order.MoveToFront(el)
+		return el.Value.(*limiterEntry).limiter
+	}
+
+	if k.order.Len() >= k.capacity {
+		oldest := k.order.Back()
+		if oldest != nil {
+			k.order.Remove(oldest)
+			delete(k.entries, oldest.Value.(*limiterEntry).key)
+		}
+	}
+
+	limiter := rate.NewLimiter(rate.Limit(float64(k.cfg.RequestsPerMinute)/60), k.cfg.Burst)
+	el := k.order.PushFront(&limiterEntry{key: key, limiter: limiter})
+	k.entries[key] = el
+	return limiter
+}
+
+// RateLimit throttles requests independently per key (as produced by
+// keyFunc), rejecting with 429 once cfg's budget is exhausted. Routes apply
+// it with different cfg/keyFunc pairs -- a strict by-IP limit on
+// /auth/login and /auth/register to blunt credential stuffing, a looser
+// by-user one on general API traffic -- so each gets its own *keyedLimiters.
+func RateLimit(cfg config.RateLimit, keyFunc KeyFunc) func(http.Handler) http.Handler {
+	limiters := newKeyedLimiters(cfg, keyedLimiterCacheSize)
+	retryAfter := time.Minute / time.Duration(max(cfg.RequestsPerMinute, 1))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limiter := limiters.get(keyFunc(r))
+			if !limiter.Allow() {
+				respondTooManyRequests(w, retryAfter)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"testing"
+
+	"github.com/self-learning/backend/internal/config"
+)
+
+// TestKeyedLimitersEvictsLeastRecentlyUsed exercises keyedLimiters' eviction
+// path directly: once capacity is exceeded, the least recently touched key
+// should be dropped so an attacker cycling through keys can't grow the map
+// without bound.
+func TestKeyedLimitersEvictsLeastRecentlyUsed(t *testing.T) {
+	limiters := newKeyedLimiters(config.RateLimit{RequestsPerMinute: 60, Burst: 60}, 2)
+
+	first := limiters.get("a")
+	limiters.get("b")
+
+	// Touch "a" again so "b" becomes the least recently used entry.
+	limiters.get("a")
+
+	// Adding a third key should evict "b", not "a".
+	limiters.get("c")
+
+	if len(limiters.entries) != 2 {
+		t.Fatalf("len(entries) = %d, want 2", len(limiters.entries))
+	}
+	if _, ok := limiters.entries["b"]; ok {
+		t.Error("\"b\" should have been evicted as the least recently used entry")
+	}
+	if _, ok := limiters.entries["a"]; !ok {
+		t.Error("\"a\" should still be present, it was touched most recently")
+	}
+	if limiters.get("a") != first {
+		t.Error("get(\"a\") returned a new limiter; \"a\" should not have been evicted")
+	}
+}
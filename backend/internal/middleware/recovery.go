@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+)
+
+// Recovery catches a panic anywhere downstream, logs it tagged with the
+// request's ID (so it can be tied back to the RequestLogger line for the
+// same request) along with the goroutine stack trace net/http's default
+// recoverer would otherwise have logged, and responds 500 instead of letting
+// the connection die with no body.
+func Recovery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if err := recover(); err != nil {
+				slog.Error("panic_recovered",
+					"error", err,
+					"path", r.URL.Path,
+					"request_id", RequestIDFromContext(r.Context()),
+					"stack", string(debug.Stack()),
+				)
+				respondInternalError(w, "Internal server error")
+			}
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -3,6 +3,8 @@ package middleware
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/self-learning/backend/internal/models"
 )
@@ -20,6 +22,18 @@ func respondError(w http.ResponseWriter, status int, errorCode, message string)
 
 // respondUnauthorized sends a 401 error response
 func respondUnauthorized(w http.ResponseWriter, message string) {
-	w.Header().Set("WWW-Authenticate", `Basic realm="Restricted"`)
+	w.Header().Set("WWW-Authenticate", `Bearer realm="Restricted"`)
 	respondError(w, http.StatusUnauthorized, "unauthorized", message)
-}
\ No newline at end of file
+}
+
+// respondTooManyRequests sends a 429 error response, telling the client how
+// long to back off via Retry-After.
+func respondTooManyRequests(w http.ResponseWriter, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+	respondError(w, http.StatusTooManyRequests, "rate_limited", "Too many requests, please try again later")
+}
+
+// respondInternalError sends a 500 error response
+func respondInternalError(w http.ResponseWriter, message string) {
+	respondError(w, http.StatusInternalServerError, "internal_error", message)
+}
@@ -33,6 +33,7 @@ type Account struct {
 
 	LastUpdatedAt   time.Time
 	LastSyncAttempt time.Time
+	LastError       string
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
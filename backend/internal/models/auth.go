@@ -6,8 +6,36 @@ type LoginRequest struct {
 }
 
 type LoginResponse struct {
-	User  UserResponse `json:"user"`
-	Token string       `json:"token"`
+	User         UserResponse `json:"user"`
+	Token        string       `json:"token"`
+	RefreshToken string       `json:"refresh_token"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type RefreshResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// LoginProvidersResponse lists the OAuth2/OIDC providers a frontend can
+// offer a "Sign in with ..." button for.
+type LoginProvidersResponse struct {
+	Providers []string `json:"providers"`
+}
+
+// UserResponse is the public view of a User returned by the auth endpoints;
+// it deliberately omits PasswordHash.
+type UserResponse struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
 }
 
 type RegisterRequest struct {
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/base64"
+	"errors"
+	"io"
+)
+
+// providerTokenKey encrypts EncryptedString columns at rest. Must be exactly
+// 32 bytes (AES-256); set once at startup via SetProviderTokenKey from
+// config.Config.ProviderTokenKey, since GORM's Valuer/Scanner hooks don't
+// carry any per-call state of their own.
+var providerTokenKey []byte
+
+// SetProviderTokenKey installs the AES-256 key EncryptedString encrypts and
+// decrypts with. Callers (database.Connect) must call this before any
+// EncryptedString column is read or written.
+func SetProviderTokenKey(key []byte) {
+	providerTokenKey = key
+}
+
+// EncryptedString is a GORM column type that transparently AES-GCM encrypts
+// its value before it touches the database and decrypts it on read, so
+// access/refresh tokens are never stored in plaintext.
+type EncryptedString string
+
+// Value implements driver.Valuer.
+func (e EncryptedString) Value() (driver.Value, error) {
+	if e == "" {
+		return "", nil
+	}
+
+	gcm, err := newGCM()
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	ciphertext := gcm.Seal(nonce, nonce, []byte(e), nil)
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// Scan implements sql.Scanner.
+func (e *EncryptedString) Scan(value interface{}) error {
+	if value == nil {
+		*e = ""
+		return nil
+	}
+
+	var raw string
+	switch v := value.(type) {
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return errors.New("models: EncryptedString.Scan: unsupported type")
+	}
+	if raw == "" {
+		*e = ""
+		return nil
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil {
+		return err
+	}
+
+	gcm, err := newGCM()
+	if err != nil {
+		return err
+	}
+
+	nonceSize := gcm.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return errors.New("models: EncryptedString.Scan: ciphertext too short")
+	}
+	nonce, ct := ciphertext[:nonceSize], ciphertext[nonceSize:]
+
+	plaintext, err := gcm.Open(nil, nonce, ct, nil)
+	if err != nil {
+		return err
+	}
+
+	*e = EncryptedString(plaintext)
+	return nil
+}
+
+// GormDataType tells GORM's migrator which column type to use for this type.
+func (EncryptedString) GormDataType() string {
+	return "text"
+}
+
+func newGCM() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(providerTokenKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
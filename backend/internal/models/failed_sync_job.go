@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// FailedSyncJob is the Dead Letter Queue row for a SyncJob that failed
+// somewhere in the Normalizer's pipeline. It's upserted on every failure
+// (keyed by AccountID+S3Path) until either the job succeeds -- at which
+// point the row is deleted -- or AttemptCount exhausts the retry budget and
+// DeadLetteredAt is stamped.
+type FailedSyncJob struct {
+	ID        string `gorm:"primaryKey;type:string"`
+	AccountID string `gorm:"index;uniqueIndex:idx_failed_sync_jobs_account_s3path"`
+	S3Path    string `gorm:"uniqueIndex:idx_failed_sync_jobs_account_s3path"`
+	FetchedAt time.Time
+
+	AttemptCount   int
+	LastError      string
+	NextRetryAt    *time.Time
+	DeadLetteredAt *time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
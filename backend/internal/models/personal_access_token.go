@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// PersonalAccessToken is a scoped, short-lived credential for machine
+// clients (e.g. the ingestion pipeline, if it's ever split out of this
+// process) that can't do an interactive login. TokenHash is the SHA-256 of
+// the plaintext token; the plaintext itself is only ever returned once, by
+// the handler that mints it.
+type PersonalAccessToken struct {
+	ID         string `gorm:"primaryKey;type:string"`
+	UserID     string `gorm:"index;not null"`
+	Name       string
+	TokenHash  string `gorm:"uniqueIndex;not null"`
+	Scopes     StringSlice
+	ExpiresAt  time.Time
+	LastUsedAt *time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// HasScope reports whether the token was granted scope.
+func (t *PersonalAccessToken) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type CreateTokenRequest struct {
+	Name       string   `json:"name" validate:"required"`
+	Scopes     []string `json:"scopes" validate:"required,min=1"`
+	TTLSeconds int      `json:"ttl_seconds"`
+}
+
+// CreateTokenResponse carries the plaintext token. It's only ever sent once,
+// in the response to the POST /api/v1/tokens call that minted it.
+type CreateTokenResponse struct {
+	ID        string    `json:"id"`
+	Token     string    `json:"token"`
+	Name      string    `json:"name"`
+	Scopes    []string  `json:"scopes"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// ProviderToken holds the OAuth2 credentials the ingestion pipeline uses to
+// pull data from an Account's bank/card provider.
+type ProviderToken struct {
+	ID           string `gorm:"primaryKey;type:string"`
+	AccountID    string `gorm:"uniqueIndex"`
+	AccessToken  EncryptedString
+	RefreshToken EncryptedString
+	ExpiresAt    time.Time
+	Scope        string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
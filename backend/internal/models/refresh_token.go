@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// RefreshToken is a long-lived credential traded for a new access/refresh
+// pair at POST /api/v1/auth/refresh. HashedToken is the SHA-256 of the
+// plaintext; the plaintext itself is only ever returned once, at login or
+// refresh time. ReplacedBy names the token that superseded this one, so a
+// revoked token presented again lets Refresh detect reuse and revoke the
+// whole chain.
+type RefreshToken struct {
+	ID          string `gorm:"primaryKey;type:string"`
+	UserID      string `gorm:"index;not null"`
+	HashedToken string `gorm:"uniqueIndex;not null"`
+	ExpiresAt   time.Time
+	RevokedAt   *time.Time
+	ReplacedBy  string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
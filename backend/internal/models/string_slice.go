@@ -0,0 +1,53 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// StringSlice is a GORM column type that stores a []string as a single JSON
+// TEXT column, for cases like PersonalAccessToken.Scopes where a join table
+// would be overkill.
+type StringSlice []string
+
+// Value implements driver.Valuer.
+func (s StringSlice) Value() (driver.Value, error) {
+	if s == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner.
+func (s *StringSlice) Scan(value interface{}) error {
+	if value == nil {
+		*s = nil
+		return nil
+	}
+
+	var raw []byte
+	switch v := value.(type) {
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return errors.New("models: StringSlice.Scan: unsupported type")
+	}
+	if len(raw) == 0 {
+		*s = nil
+		return nil
+	}
+
+	return json.Unmarshal(raw, s)
+}
+
+// GormDataType tells GORM's migrator which column type to use for this type.
+func (StringSlice) GormDataType() string {
+	return "text"
+}
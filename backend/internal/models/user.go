@@ -7,10 +7,38 @@ import (
 )
 
 type User struct {
-	ID        string `gorm:"primaryKey;type:string"`
-	Username  string `gorm:"uniqueIndex;not null"`
-	Email     string `gorm:"uniqueIndex"`
+	ID       string `gorm:"primaryKey;type:string"`
+	Username string `gorm:"uniqueIndex;not null"`
+
+	// Email is a pointer because OAuth providers don't always return one
+	// (e.g. a GitHub account with no public/verified email) -- the unique
+	// index still holds with multiple such users since NULL is never
+	// considered equal to NULL.
+	Email        *string `gorm:"uniqueIndex"`
+	PasswordHash string  `gorm:"not null"`
+
+	// Provider/ProviderSubject identify the OAuth2/OIDC login provider
+	// ("google", "github") and its stable subject ID for a user that signed
+	// up via one of them. Both are nil for password-only users; the unique
+	// index still holds because a pair of NULLs is never considered equal.
+	Provider        *string `gorm:"uniqueIndex:idx_users_provider_subject"`
+	ProviderSubject *string `gorm:"uniqueIndex:idx_users_provider_subject"`
+
+	// IsAdmin gates minting admin-scoped personal access tokens (see
+	// PATService.Mint) -- there's no broader role system, just this one
+	// flag, set by an operator directly in the database or via the seed.
+	IsAdmin bool `gorm:"not null;default:false"`
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt gorm.DeletedAt `gorm:"index"`
 }
+
+// EmailOrEmpty returns Email dereferenced, or "" for a user with none (e.g.
+// an OAuth signup whose provider didn't return one).
+func (u *User) EmailOrEmpty() string {
+	if u.Email == nil {
+		return ""
+	}
+	return *u.Email
+}
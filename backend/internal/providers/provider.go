@@ -0,0 +1,48 @@
+// Package providers talks to the bank/card OAuth2 APIs that back each
+// Account.Provider value ("bank_leumi", "visa", ...).
+package providers
+
+import (
+	"context"
+	"time"
+)
+
+// Token is the OAuth2 credential pair returned by a provider for one account.
+type Token struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
+	Scope        string
+}
+
+// RawTxn is one transaction as reported by a provider, before normalization.
+type RawTxn struct {
+	ID       string    `json:"id"`
+	Amount   float64   `json:"amount"`
+	Merchant string    `json:"merchant"`
+	Date     time.Time `json:"date"`
+	Currency string    `json:"currency"`
+	Status   string    `json:"status"`
+}
+
+// Provider is the OAuth2 connector for a single bank/card data source.
+// Implementations are looked up by Account.Provider / config key (e.g.
+// "bank_leumi"), so the ingestion pipeline never branches on provider name.
+type Provider interface {
+	// AuthURL builds the provider's authorization URL for the given
+	// anti-CSRF state value.
+	AuthURL(state string) string
+
+	// Exchange trades an authorization code (from the OAuth2 callback) for
+	// a token pair.
+	Exchange(ctx context.Context, code string) (*Token, error)
+
+	// Refresh exchanges a refresh token for a new access token. Callers
+	// should treat an "invalid_grant" error as the account needing
+	// reauthorization rather than a transient failure.
+	Refresh(ctx context.Context, token *Token) (*Token, error)
+
+	// FetchTransactions returns every transaction posted since the given
+	// cursor, authenticated with token.
+	FetchTransactions(ctx context.Context, token *Token, since time.Time) ([]RawTxn, error)
+}
@@ -0,0 +1,13 @@
+package providers
+
+import "github.com/self-learning/backend/internal/config"
+
+// NewRegistry builds the set of Providers the ingestion pipeline can fetch
+// from, keyed by the same provider name stored on Account.Provider.
+func NewRegistry(cfgs map[string]config.BankProviderConfig) map[string]Provider {
+	registry := make(map[string]Provider, len(cfgs))
+	for name, cfg := range cfgs {
+		registry[name] = NewOAuthProvider(cfg)
+	}
+	return registry
+}
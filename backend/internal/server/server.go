@@ -1,74 +1,232 @@
 package server
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/self-learning/backend/internal/config"
-	"github.com/self-learning/backend/internal/database"
 	"github.com/self-learning/backend/internal/handlers"
 	"github.com/self-learning/backend/internal/ingestion"
 	"github.com/self-learning/backend/internal/middleware"
-	"github.com/self-learning/backend/internal/services"
+	"github.com/self-learning/backend/internal/providers"
+	"go.uber.org/fx"
 	"gorm.io/gorm"
 )
 
+// HTTP server timeouts, chosen to cut off a slow/stalled client (the classic
+// Slowloris shape) well before it can tie up a worker indefinitely.
+const (
+	readHeaderTimeout = 5 * time.Second
+	readTimeout       = 15 * time.Second
+	writeTimeout      = 30 * time.Second
+	idleTimeout       = 60 * time.Second
+	maxHeaderBytes    = 1 << 20
+)
+
 type Server struct {
-	config    *config.Config
-	db        *gorm.DB
-	router    *mux.Router
-	handlers  *handlers.Handler
-	startTime time.Time
+	config     *config.Config
+	db         *gorm.DB
+	router     *mux.Router
+	handlers   *handlers.Handler
+	httpServer *http.Server
+	startTime  time.Time
+
+	queue     chan ingestion.SyncJob
+	bgCancel  context.CancelFunc
+	producers sync.WaitGroup // StartFetcherLoop, StartRetryLoop -- writers onto queue
+	consumers sync.WaitGroup // StartNormalizerLoop -- the only reader of queue
 }
 
-func New(cfg *config.Config) *Server {
-	// 1. Initialize Database
-	db := database.Connect(cfg.DatabasePath)
+// New builds the router around an already-wired Handler and registers the
+// lifecycle (OnStart/OnStop) for both the HTTP server and the background
+// fetcher/normalizer/retry loops, since Shutdown has to coordinate all of
+// them to drain cleanly. Callers own constructing the rest of the
+// dependencies (see internal/app for the composition root).
+func New(
+	lc fx.Lifecycle,
+	cfg *config.Config,
+	db *gorm.DB,
+	cfgStore *config.IngestionConfigStore,
+	registry map[string]providers.Provider,
+	queue chan ingestion.SyncJob,
+	h *handlers.Handler,
+) *Server {
+	s := &Server{
+		config:    cfg,
+		db:        db,
+		handlers:  h,
+		queue:     queue,
+		startTime: time.Now(),
+	}
+	s.setupRoutes()
 
-	// 2. Initialize services
-	accountService := services.NewAccountService(db)
+	s.httpServer = &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           s.router,
+		ReadHeaderTimeout: readHeaderTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+		IdleTimeout:       idleTimeout,
+		MaxHeaderBytes:    maxHeaderBytes,
+	}
 
-	// 3. Initialize handlers
-	h := handlers.New(accountService)
+	bgCtx, cancel := context.WithCancel(context.Background())
+	s.bgCancel = cancel
 
-	// 4. Start background workers
-	queue := make(chan ingestion.SyncJob, 100)
-	go ingestion.StartFetcherLoop(db, queue)
-	go ingestion.StartNormalizerLoop(db, queue)
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			s.producers.Add(2)
+			go func() {
+				defer s.producers.Done()
+				ingestion.StartFetcherLoop(bgCtx, db, cfg.DBDriver, registry, queue, cfgStore)
+			}()
+			go func() {
+				defer s.producers.Done()
+				ingestion.StartRetryLoop(bgCtx, db, queue)
+			}()
 
-	log.Println("Background ingestion workers started")
+			s.consumers.Add(1)
+			go func() {
+				defer s.consumers.Done()
+				ingestion.StartNormalizerLoop(bgCtx, db, queue)
+			}()
 
-	return &Server{
-		config:    cfg,
-		db:        db,
-		handlers:  h,
-		startTime: time.Now(),
+			go func() {
+				log.Printf("Server starting on port %s", cfg.Port)
+				if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+					log.Fatal(err)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return s.Shutdown(ctx)
+		},
+	})
+
+	return s
+}
+
+// Shutdown stops the HTTP server from accepting new requests, then drains
+// the ingestion pipeline: cancelling the fetcher/retry-loop context so they
+// stop producing, waiting for them to actually exit, only then closing
+// queue (closing it while a producer could still be mid-send would panic),
+// and finally waiting for the normalizer to drain whatever was left before
+// closing the DB connection. Each wait is bounded by ctx's deadline -- a
+// producer or consumer that's wedged on a slow provider call no longer
+// holds shutdown open past it.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		return err
 	}
+
+	s.bgCancel()
+	if err := waitWithContext(ctx, &s.producers); err != nil {
+		return err
+	}
+	close(s.queue)
+	if err := waitWithContext(ctx, &s.consumers); err != nil {
+		return err
+	}
+
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
 }
 
-func (s *Server) Start() error {
-	s.setupRoutes()
-	return http.ListenAndServe(":"+s.config.Port, s.router)
+// waitWithContext blocks on wg like wg.Wait(), but returns ctx.Err() if ctx
+// is cancelled or times out first.
+func waitWithContext(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 func (s *Server) setupRoutes() {
 	s.router = mux.NewRouter()
 
-	// Global middleware
-	s.router.Use(middleware.CORS)
+	// Global middleware. RequestID runs first so the ID it stamps is in
+	// context by the time RequestLogger logs the line for this request.
+	s.router.Use(middleware.RequestID)
+	s.router.Use(middleware.RequestLogger)
+	s.router.Use(middleware.CORS(s.config.CORSAllowedOrigins))
 	s.router.Use(middleware.Recovery)
 
+	// gorilla/mux only runs router-level middleware (including CORS above)
+	// for a request that matches a registered route, and no route below
+	// registers OPTIONS -- so a browser's preflight for a request carrying
+	// Authorization (not a CORS-safelisted header) would 404 instead of
+	// getting CORS's short-circuited response without this catch-all.
+	s.router.Methods(http.MethodOptions).PathPrefix("/").HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
 	// Health check (no auth required)
 	s.router.HandleFunc("/health", s.handlers.HealthCheck).Methods("GET")
 
-	// API routes with Basic Auth protection
+	// Login/refresh/logout issue or consume the refresh token, so they live
+	// outside the access-token-protected subrouter.
+	s.router.HandleFunc("/api/v1/auth/refresh", s.handlers.Refresh).Methods("POST")
+	s.router.HandleFunc("/api/v1/auth/logout", s.handlers.Logout).Methods("POST")
+
+	// Register/login are credential-stuffing targets, so they get their own
+	// strict by-IP limiter instead of the looser one on general API traffic.
+	strictAuth := s.router.PathPrefix("/api/v1/auth").Subrouter()
+	strictAuth.Use(middleware.RateLimit(s.config.RateLimits["auth"], middleware.ByIP(s.config.TrustedProxies)))
+	strictAuth.HandleFunc("/register", s.handlers.Register).Methods("POST")
+	strictAuth.HandleFunc("/login", s.handlers.Login).Methods("POST")
+
+	// OAuth2/OIDC login (alongside password login above), one provider per
+	// name under internal/auth. /providers lets the frontend discover which
+	// ones are configured instead of hardcoding the button list.
+	s.router.HandleFunc("/api/v1/auth/providers", s.handlers.LoginProviders).Methods("GET")
+	s.router.HandleFunc("/api/v1/auth/oauth/{provider}/login", s.handlers.OAuthLoginStart).Methods("GET")
+	s.router.HandleFunc("/api/v1/auth/oauth/{provider}/callback", s.handlers.OAuthLoginCallback).Methods("GET")
+
+	// API routes, protected by either a personal access token or a
+	// login-issued JWT.
 	api := s.router.PathPrefix("/api/v1").Subrouter()
+	api.Use(middleware.PATAuth(s.db, ""))
+	api.Use(middleware.JWTAuth([]byte(s.config.JWTSecret)))
+	api.Use(middleware.RateLimit(s.config.RateLimits["api"], middleware.ByUser(s.config.TrustedProxies)))
 
-	// Apply Basic Auth middleware to all API routes
-	api.Use(middleware.BasicAuth)
-	
 	// Account routes
 	api.HandleFunc("/accounts", s.handlers.GetUserAccounts).Methods("GET")
+
+	// Personal access tokens, for machine clients that can't do an
+	// interactive login (see middleware.PATAuth).
+	api.HandleFunc("/tokens", s.handlers.CreateToken).Methods("POST")
+	api.HandleFunc("/tokens/{id}", s.handlers.RevokeToken).Methods("DELETE")
+
+	// Provider OAuth2 connect/callback. Connect requires an authenticated
+	// user; the callback is hit by the provider's redirect and relies on the
+	// signed state cookie instead.
+	authProviders := s.router.PathPrefix("/providers").Subrouter()
+	authProviders.Use(middleware.PATAuth(s.db, ""))
+	authProviders.Use(middleware.JWTAuth([]byte(s.config.JWTSecret)))
+	authProviders.HandleFunc("/{name}/connect", s.handlers.ProviderConnect).Methods("GET")
+
+	providerCallbacks := s.router.PathPrefix("/providers").Subrouter()
+	providerCallbacks.HandleFunc("/{name}/callback", s.handlers.ProviderCallback).Methods("GET")
+
+	// Dead Letter Queue inspection/replay, restricted to PATs minted with
+	// the "admin" scope -- there's no interactive admin login, so unlike the
+	// routes above this doesn't also accept a JWT.
+	admin := s.router.PathPrefix("/api/v1/admin").Subrouter()
+	admin.Use(middleware.RequirePAT(s.db, "admin"))
+	admin.HandleFunc("/dlq", s.handlers.ListDLQ).Methods("GET")
+	admin.HandleFunc("/dlq/{id}/replay", s.handlers.ReplayDLQ).Methods("POST")
 }
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/self-learning/backend/internal/auth"
+	"github.com/self-learning/backend/internal/config"
+	"github.com/self-learning/backend/internal/models"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidCredentials is returned by Login when the username doesn't exist
+// or the password doesn't match.
+var ErrInvalidCredentials = errors.New("invalid username or password")
+
+// ErrUserExists is returned by Register when the username or email is
+// already taken.
+var ErrUserExists = errors.New("username or email already taken")
+
+// registerBcryptCost is deliberately higher than bcrypt.DefaultCost (10):
+// registration is rare enough that the extra hashing time is worth the
+// stronger resistance to offline cracking.
+const registerBcryptCost = 12
+
+// ErrInvalidRefreshToken covers an unknown, expired, or already-revoked
+// refresh token.
+var ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+
+// refreshTokenTTL is how long a refresh token stays valid if it's never
+// rotated, revoked, or superseded by a fresh login.
+const refreshTokenTTL = 30 * 24 * time.Hour
+
+// AuthService validates login credentials against the persisted User table
+// and issues/rotates access+refresh token pairs for the ones that check out.
+type AuthService struct {
+	db        *gorm.DB
+	jwtSecret []byte
+}
+
+func NewAuthService(db *gorm.DB, cfg *config.Config) *AuthService {
+	return &AuthService{db: db, jwtSecret: []byte(cfg.JWTSecret)}
+}
+
+// Login checks a username/password pair against the users table and, on
+// success, returns the authenticated user alongside a fresh access/refresh
+// token pair.
+func (s *AuthService) Login(username, password string) (user *models.User, accessToken, refreshToken string, err error) {
+	user = &models.User{}
+	if err := s.db.Where("username = ?", username).First(user).Error; err != nil {
+		return nil, "", "", ErrInvalidCredentials
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return nil, "", "", ErrInvalidCredentials
+	}
+
+	accessToken, refreshToken, _, err = s.issueTokenPair(s.db, user.ID, user.Username)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	return user, accessToken, refreshToken, nil
+}
+
+// Register hashes password at registerBcryptCost and inserts a new User row,
+// returning ErrUserExists if the username or email is already taken. The
+// uniqueness check happens at Create time via the username/email unique
+// indexes rather than a preceding count, so two concurrent registrations for
+// the same username or email can't both pass a check-then-insert race.
+func (s *AuthService) Register(username, email, password string) (*models.User, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), registerBcryptCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		ID:           uuid.New().String(),
+		Username:     username,
+		Email:        &email,
+		PasswordHash: string(hash),
+	}
+	if err := s.db.Create(user).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrUserExists
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// OAuthLogin upserts the User matching (provider, info.Subject) -- creating
+// one on first sign-in -- and issues it a fresh access/refresh token pair,
+// exactly like Login does for the password flow.
+func (s *AuthService) OAuthLogin(provider string, info *auth.OAuthUserInfo) (user *models.User, accessToken, refreshToken string, err error) {
+	user = &models.User{}
+	err = s.db.Where("provider = ? AND provider_subject = ?", provider, info.Subject).First(user).Error
+	switch {
+	case err == nil:
+		// Existing OAuth user; nothing to upsert beyond issuing tokens.
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		user = &models.User{
+			ID:              uuid.New().String(),
+			Username:        oauthUsername(provider, info),
+			Email:           emailOrNil(info.Email),
+			Provider:        &provider,
+			ProviderSubject: &info.Subject,
+		}
+		if err := s.db.Create(user).Error; err != nil {
+			return nil, "", "", err
+		}
+	default:
+		return nil, "", "", err
+	}
+
+	accessToken, refreshToken, _, err = s.issueTokenPair(s.db, user.ID, user.Username)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	return user, accessToken, refreshToken, nil
+}
+
+// oauthUsername derives a Username for a brand-new OAuth-provisioned user.
+// Email is unique and human-readable, so it's preferred; providers that
+// don't return one (e.g. GitHub with no verified public email) fall back to
+// a provider-qualified subject id.
+func oauthUsername(provider string, info *auth.OAuthUserInfo) string {
+	if info.Email != "" {
+		return info.Email
+	}
+	return provider + ":" + info.Subject
+}
+
+// emailOrNil returns nil for an empty email rather than a pointer to "",
+// since Email is unique and a GitHub account with no public/verified email
+// must not collide with another user's equally-empty one.
+func emailOrNil(email string) *string {
+	if email == "" {
+		return nil
+	}
+	return &email
+}
+
+// Refresh rotates refreshToken: the presented token is revoked and replaced
+// by a freshly issued pair. Presenting a token that's already been revoked
+// is treated as reuse of a stolen token, so it revokes every other active
+// token belonging to that user and forces re-login.
+//
+// The revoke is a single conditional UPDATE ... WHERE revoked_at IS NULL
+// rather than a read-then-write, and it happens in the same transaction as
+// minting the replacement pair -- two concurrent requests presenting the
+// same token must not both pass a revoked check and walk away with a valid
+// pair each, which is exactly the race Register's Create-then-translate
+// pattern guards against for a different check-then-act.
+func (s *AuthService) Refresh(refreshToken string) (accessToken, newRefreshToken string, err error) {
+	var stored models.RefreshToken
+	if err := s.db.Where("hashed_token = ?", auth.HashRefreshToken(refreshToken)).First(&stored).Error; err != nil {
+		return "", "", ErrInvalidRefreshToken
+	}
+
+	if stored.RevokedAt != nil {
+		s.revokeAllForUser(stored.UserID)
+		return "", "", ErrInvalidRefreshToken
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return "", "", ErrInvalidRefreshToken
+	}
+
+	var user models.User
+	if err := s.db.Where("id = ?", stored.UserID).First(&user).Error; err != nil {
+		return "", "", ErrInvalidRefreshToken
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", stored.ID).
+			Update("revoked_at", time.Now())
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrInvalidRefreshToken
+		}
+
+		var newRow *models.RefreshToken
+		accessToken, newRefreshToken, newRow, err = s.issueTokenPair(tx, user.ID, user.Username)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&models.RefreshToken{}).Where("id = ?", stored.ID).Update("replaced_by", newRow.ID).Error
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidRefreshToken) {
+			return "", "", ErrInvalidRefreshToken
+		}
+		return "", "", err
+	}
+
+	return accessToken, newRefreshToken, nil
+}
+
+// Logout revokes refreshToken so it can no longer be redeemed for a new
+// token pair.
+func (s *AuthService) Logout(refreshToken string) error {
+	res := s.db.Model(&models.RefreshToken{}).
+		Where("hashed_token = ? AND revoked_at IS NULL", auth.HashRefreshToken(refreshToken)).
+		Update("revoked_at", time.Now())
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrInvalidRefreshToken
+	}
+	return nil
+}
+
+// issueTokenPair signs a fresh access token and persists a new refresh
+// token row for userID, returning both in plaintext alongside the row so
+// callers that are rotating an existing token can link it as ReplacedBy.
+func (s *AuthService) issueTokenPair(db *gorm.DB, userID, username string) (accessToken, refreshToken string, row *models.RefreshToken, err error) {
+	accessToken, err = auth.GenerateToken(userID, username, s.jwtSecret)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	plaintext, hash, err := auth.GenerateRefreshToken()
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	row = &models.RefreshToken{
+		ID:          uuid.New().String(),
+		UserID:      userID,
+		HashedToken: hash,
+		ExpiresAt:   time.Now().Add(refreshTokenTTL),
+	}
+	if err := db.Create(row).Error; err != nil {
+		return "", "", nil, err
+	}
+
+	return accessToken, plaintext, row, nil
+}
+
+// revokeAllForUser marks every still-active refresh token belonging to
+// userID as revoked, used when reuse of an already-rotated token indicates
+// the chain may be compromised.
+func (s *AuthService) revokeAllForUser(userID string) {
+	s.db.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now())
+}
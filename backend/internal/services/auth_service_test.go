@@ -0,0 +1,56 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/self-learning/backend/internal/config"
+	"github.com/self-learning/backend/internal/services"
+	"github.com/self-learning/backend/internal/testutil"
+)
+
+func newAuthService(t *testing.T) *services.AuthService {
+	t.Helper()
+	db := testutil.NewTestDB(t)
+	return services.NewAuthService(db, &config.Config{JWTSecret: "test-secret"})
+}
+
+// TestRefreshReuseRevokesTheWholeChain exercises the reuse-detection branch:
+// once a refresh token has been rotated, presenting the old one again is
+// treated as a stolen token and must revoke every other token for that user,
+// not just the one being reused.
+func TestRefreshReuseRevokesTheWholeChain(t *testing.T) {
+	authService := newAuthService(t)
+
+	_, err := authService.Register("alice", "alice@example.com", "hunter22")
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	_, _, firstRefresh, err := authService.Login("alice", "hunter22")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	_, secondRefresh, err := authService.Refresh(firstRefresh)
+	if err != nil {
+		t.Fatalf("first Refresh: %v", err)
+	}
+
+	// secondRefresh is the only currently-valid token. Replaying the
+	// already-rotated firstRefresh should fail and burn secondRefresh too.
+	if _, _, err := authService.Refresh(firstRefresh); err != services.ErrInvalidRefreshToken {
+		t.Fatalf("Refresh(reused token) = %v, want %v", err, services.ErrInvalidRefreshToken)
+	}
+
+	if _, _, err := authService.Refresh(secondRefresh); err != services.ErrInvalidRefreshToken {
+		t.Errorf("Refresh(secondRefresh) after reuse detected = %v, want %v (whole chain should be revoked)", err, services.ErrInvalidRefreshToken)
+	}
+}
+
+func TestRefreshRejectsAnUnknownToken(t *testing.T) {
+	authService := newAuthService(t)
+
+	if _, _, err := authService.Refresh("not-a-real-token"); err != services.ErrInvalidRefreshToken {
+		t.Errorf("Refresh(unknown) = %v, want %v", err, services.ErrInvalidRefreshToken)
+	}
+}
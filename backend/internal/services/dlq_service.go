@@ -0,0 +1,51 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/self-learning/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErrDLQJobNotFound is returned by Replay when id doesn't match a
+// failed_sync_jobs row.
+var ErrDLQJobNotFound = errors.New("dlq job not found")
+
+// DLQService gives operators visibility into and control over ingestion
+// jobs the normalizer has recorded as failed, including the ones it
+// dead-lettered after exhausting its retries.
+type DLQService struct {
+	db *gorm.DB
+}
+
+func NewDLQService(db *gorm.DB) *DLQService {
+	return &DLQService{db: db}
+}
+
+// List returns every DLQ row, most recently updated first.
+func (s *DLQService) List() ([]models.FailedSyncJob, error) {
+	var rows []models.FailedSyncJob
+	err := s.db.Order("updated_at DESC").Find(&rows).Error
+	return rows, err
+}
+
+// Replay clears id's dead_lettered_at and schedules it for an immediate
+// retry, which StartRetryLoop picks up on its next poll. AttemptCount is
+// left as-is so the history of a poisoned job isn't lost.
+func (s *DLQService) Replay(id string) error {
+	now := time.Now()
+	res := s.db.Model(&models.FailedSyncJob{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"dead_lettered_at": nil,
+			"next_retry_at":    now,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrDLQJobNotFound
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/self-learning/backend/internal/auth"
+	"github.com/self-learning/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+var ErrTokenNotFound = errors.New("personal access token not found")
+
+// ErrInsufficientScope is returned by Mint when the requesting user isn't
+// allowed to mint a token carrying one of the requested scopes.
+var ErrInsufficientScope = errors.New("not authorized to mint a token with the requested scope")
+
+// adminScope gates minting a PAT with admin privileges (see
+// middleware.RequirePAT's use on the DLQ routes). There's no broader role
+// system in this data model, just models.User.IsAdmin.
+const adminScope = "admin"
+
+// PATService mints and revokes the scoped, short-lived personal access
+// tokens machine clients use instead of an interactive login.
+type PATService struct {
+	db *gorm.DB
+}
+
+func NewPATService(db *gorm.DB) *PATService {
+	return &PATService{
+		db: db,
+	}
+}
+
+// Mint creates a PersonalAccessToken for userID and returns the plaintext
+// token alongside the persisted row. The plaintext is only ever available
+// here; only its hash is stored.
+func (s *PATService) Mint(userID, name string, scopes []string, ttl time.Duration) (string, *models.PersonalAccessToken, error) {
+	if err := s.authorizeScopes(userID, scopes); err != nil {
+		return "", nil, err
+	}
+
+	plaintext, hash, err := auth.GeneratePAT()
+	if err != nil {
+		return "", nil, err
+	}
+
+	pat := &models.PersonalAccessToken{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Name:      name,
+		TokenHash: hash,
+		Scopes:    scopes,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := s.db.Create(pat).Error; err != nil {
+		return "", nil, err
+	}
+
+	return plaintext, pat, nil
+}
+
+// authorizeScopes rejects minting a token carrying adminScope unless userID
+// belongs to a models.User with IsAdmin set -- otherwise any self-registered
+// user could request an admin-scoped PAT and walk straight through
+// middleware.RequirePAT(db, "admin") on the DLQ routes.
+func (s *PATService) authorizeScopes(userID string, scopes []string) error {
+	requestsAdmin := false
+	for _, scope := range scopes {
+		if scope == adminScope {
+			requestsAdmin = true
+			break
+		}
+	}
+	if !requestsAdmin {
+		return nil
+	}
+
+	var user models.User
+	if err := s.db.Select("is_admin").First(&user, "id = ?", userID).Error; err != nil {
+		return err
+	}
+	if !user.IsAdmin {
+		return ErrInsufficientScope
+	}
+	return nil
+}
+
+// Revoke deletes the token identified by id, scoped to userID so one user
+// can't revoke another user's token.
+func (s *PATService) Revoke(userID, id string) error {
+	res := s.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.PersonalAccessToken{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrTokenNotFound
+	}
+	return nil
+}
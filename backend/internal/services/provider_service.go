@@ -0,0 +1,85 @@
+package services
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/self-learning/backend/internal/models"
+	"github.com/self-learning/backend/internal/providers"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProviderService persists OAuth2 provider tokens and the accounts they
+// authorize the ingestion pipeline to sync.
+type ProviderService struct {
+	db *gorm.DB
+}
+
+func NewProviderService(db *gorm.DB) *ProviderService {
+	return &ProviderService{
+		db: db,
+	}
+}
+
+// ConnectAccount upserts the Account a provider callback is authorizing for
+// userID, keyed by (user_id, provider), and flags it for an immediate sync.
+func (s *ProviderService) ConnectAccount(userID, provider string) (*models.Account, error) {
+	var account models.Account
+	err := s.db.Where("user_id = ? AND provider = ?", userID, provider).First(&account).Error
+
+	switch {
+	case err == nil:
+		account.SyncStatus = models.StatusPendingSync
+	case err == gorm.ErrRecordNotFound:
+		account = models.Account{
+			ID:                uuid.New().String(),
+			UserID:            userID,
+			Provider:          provider,
+			ExternalAccountID: provider + ":" + userID,
+			SyncStatus:        models.StatusPendingSync,
+			CreatedAt:         time.Now(),
+		}
+	default:
+		return nil, err
+	}
+
+	account.UpdatedAt = time.Now()
+	if err := s.db.Save(&account).Error; err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// SaveToken upserts the token for accountID, keyed on the unique AccountID index.
+func (s *ProviderService) SaveToken(accountID string, token *providers.Token) error {
+	row := models.ProviderToken{
+		ID:           uuid.New().String(),
+		AccountID:    accountID,
+		AccessToken:  models.EncryptedString(token.AccessToken),
+		RefreshToken: models.EncryptedString(token.RefreshToken),
+		ExpiresAt:    token.ExpiresAt,
+		Scope:        token.Scope,
+		UpdatedAt:    time.Now(),
+	}
+
+	return s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "account_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"access_token", "refresh_token", "expires_at", "scope", "updated_at"}),
+	}).Create(&row).Error
+}
+
+// GetToken loads the persisted token for accountID.
+func (s *ProviderService) GetToken(accountID string) (*providers.Token, error) {
+	var row models.ProviderToken
+	if err := s.db.Where("account_id = ?", accountID).First(&row).Error; err != nil {
+		return nil, err
+	}
+
+	return &providers.Token{
+		AccessToken:  string(row.AccessToken),
+		RefreshToken: string(row.RefreshToken),
+		ExpiresAt:    row.ExpiresAt,
+		Scope:        row.Scope,
+	}, nil
+}